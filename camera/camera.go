@@ -0,0 +1,176 @@
+// Package camera определяет, какая часть мира видна в области экрана:
+// плавно следует за целью, не выходит за границы карты, приближает
+// и трясет обзор при ударах. Пакет не зависит от ebiten, все величины
+// в мировых единицах и пикселях экрана.
+package camera
+
+import "math"
+
+const (
+	DefaultFollowSpeed = 8.0 // Насколько быстро камера догоняет цель, 1/с
+	DefaultMinZoom     = 0.5
+	DefaultMaxZoom     = 3.0
+
+	maxShakeOffset = 12.0 // Наибольшее смещение при тряске, в пикселях экрана
+	shakeDecay     = 1.5  // Сколько силы тряски уходит за секунду
+	shakeFrequency = 30.0 // Частота колебаний при тряске, Гц
+)
+
+// Rect прямоугольник в мировых координатах; пустой прямоугольник означает
+// отсутствие границ
+type Rect struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// Empty сообщает, что границы не заданы
+func (r Rect) Empty() bool {
+	return r.MaxX <= r.MinX || r.MaxY <= r.MinY
+}
+
+// Camera обзор одной области экрана. X, Y - центр обзора в мировых координатах.
+type Camera struct {
+	X, Y        float64
+	Zoom        float64 // Дополнительное приближение поверх масштаба экрана
+	MinZoom     float64
+	MaxZoom     float64
+	FollowSpeed float64 // 0 - камера сразу встает на цель
+	Bounds      Rect    // Границы карты, за которые обзор не выходит
+
+	// Область экрана, заданная SetViewport
+	screenX, screenY float64
+	screenW, screenH float64
+	scale            float64 // Пикселей экрана на мировую единицу при Zoom = 1
+
+	shake            float64 // Сила тряски от 0 до 1
+	shakeTime        float64 // Время с начала тряски для колебаний, в секундах
+	offsetX, offsetY float64 // Текущее смещение от тряски, в пикселях экрана
+}
+
+// New создает камеру с центром в (x, y) без границ и приближения
+func New(x, y float64) *Camera {
+	return &Camera{
+		X:           x,
+		Y:           y,
+		Zoom:        1,
+		MinZoom:     DefaultMinZoom,
+		MaxZoom:     DefaultMaxZoom,
+		FollowSpeed: DefaultFollowSpeed,
+		scale:       1,
+	}
+}
+
+// SetViewport задает область экрана, в которой показывается мир, и масштаб
+// экрана (пикселей на мировую единицу без приближения)
+func (c *Camera) SetViewport(x, y, w, h, scale float64) {
+	c.screenX, c.screenY = x, y
+	c.screenW, c.screenH = w, h
+	if scale > 0 {
+		c.scale = scale
+	}
+	c.clamp()
+}
+
+// Scale возвращает итоговый масштаб: пикселей экрана на мировую единицу
+func (c *Camera) Scale() float64 {
+	return c.scale * c.Zoom
+}
+
+// Follow сдвигает камеру к цели (x, y) за время dt в секундах
+func (c *Camera) Follow(x, y, dt float64) {
+	if c.FollowSpeed <= 0 {
+		c.X, c.Y = x, y
+	} else {
+		// Экспоненциальное сглаживание не зависит от частоты тиков
+		k := 1 - math.Exp(-c.FollowSpeed*dt)
+		c.X += (x - c.X) * k
+		c.Y += (y - c.Y) * k
+	}
+	c.clamp()
+}
+
+// Snap ставит камеру на цель без сглаживания, например при появлении игрока
+func (c *Camera) Snap(x, y float64) {
+	c.X, c.Y = x, y
+	c.clamp()
+}
+
+// SetZoom задает приближение в пределах MinZoom..MaxZoom
+func (c *Camera) SetZoom(zoom float64) {
+	c.Zoom = math.Max(c.MinZoom, math.Min(c.MaxZoom, zoom))
+	c.clamp()
+}
+
+// ZoomBy умножает приближение на factor
+func (c *Camera) ZoomBy(factor float64) {
+	c.SetZoom(c.Zoom * factor)
+}
+
+// Shake добавляет тряску силой strength (от 0 до 1); сила от нескольких
+// ударов складывается, но не превышает 1
+func (c *Camera) Shake(strength float64) {
+	if c.shake <= 0 {
+		c.shakeTime = 0
+	}
+	c.shake = math.Min(1, c.shake+strength)
+}
+
+// Update затухает тряску за время dt в секундах. Колебания считаются от
+// времени, а не от случайных чисел, чтобы запись ввода воспроизводилась одинаково.
+func (c *Camera) Update(dt float64) {
+	if c.shake <= 0 {
+		c.offsetX, c.offsetY = 0, 0
+		return
+	}
+	c.shakeTime += dt
+	c.shake = math.Max(0, c.shake-shakeDecay*dt)
+
+	// Квадрат силы: слабые удары едва заметны, сильные трясут заметно
+	amount := c.shake * c.shake * maxShakeOffset
+	phase := 2 * math.Pi * shakeFrequency * c.shakeTime
+	c.offsetX = amount * math.Sin(phase)
+	c.offsetY = amount * math.Sin(phase*1.3+1)
+}
+
+// Visible возвращает видимую часть мира без учета тряски
+func (c *Camera) Visible() Rect {
+	halfW, halfH := c.halfView()
+	return Rect{c.X - halfW, c.Y - halfH, c.X + halfW, c.Y + halfH}
+}
+
+// WorldToScreen переводит мировые координаты в точку экрана
+func (c *Camera) WorldToScreen(x, y float64) (float64, float64) {
+	scale := c.Scale()
+	return (x-c.X)*scale + c.screenX + c.screenW/2 + c.offsetX,
+		(y-c.Y)*scale + c.screenY + c.screenH/2 + c.offsetY
+}
+
+// ScreenToWorld переводит точку экрана в мировые координаты
+func (c *Camera) ScreenToWorld(x, y float64) (float64, float64) {
+	scale := c.Scale()
+	return (x-c.screenX-c.screenW/2-c.offsetX)/scale + c.X,
+		(y-c.screenY-c.screenH/2-c.offsetY)/scale + c.Y
+}
+
+// halfView возвращает половину видимой области в мировых единицах
+func (c *Camera) halfView() (float64, float64) {
+	scale := c.Scale()
+	return c.screenW / scale / 2, c.screenH / scale / 2
+}
+
+// clamp не дает обзору выйти за границы карты; если карта меньше обзора,
+// она показывается по центру
+func (c *Camera) clamp() {
+	if c.Bounds.Empty() {
+		return
+	}
+	halfW, halfH := c.halfView()
+	c.X = clampAxis(c.X, halfW, c.Bounds.MinX, c.Bounds.MaxX)
+	c.Y = clampAxis(c.Y, halfH, c.Bounds.MinY, c.Bounds.MaxY)
+}
+
+func clampAxis(center, half, min, max float64) float64 {
+	if max-min <= 2*half {
+		return (min + max) / 2
+	}
+	return math.Max(min+half, math.Min(max-half, center))
+}
@@ -0,0 +1,120 @@
+package camera
+
+import (
+	"math"
+	"testing"
+
+	"main.go/view"
+)
+
+const epsilon = 1e-9
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < epsilon
+}
+
+func TestWorldToScreenRoundTrip(t *testing.T) {
+	c := New(100, 50)
+	c.SetViewport(10, 20, 800, 600, 2)
+	c.SetZoom(1.5)
+
+	x, y := c.WorldToScreen(100, 50)
+	if !near(x, 10+400) || !near(y, 20+300) {
+		t.Fatalf("центр камеры на экране = (%v, %v), ожидалось (410, 320)", x, y)
+	}
+	x, y = c.WorldToScreen(110, 50)
+	if !near(x, 410+10*3) {
+		t.Fatalf("x = %v, ожидалось %v", x, 410+10*3.0)
+	}
+	wx, wy := c.ScreenToWorld(c.WorldToScreen(-37, 12.5))
+	if !near(wx, -37) || !near(wy, 12.5) {
+		t.Fatalf("обратное преобразование = (%v, %v)", wx, wy)
+	}
+}
+
+// Указатель проходит весь путь: окно -> виртуальный экран -> мир
+func TestPointerToWorld(t *testing.T) {
+	v := view.Fit(3200, 2000, view.VirtualWidth, view.VirtualHeight) // HiDPI с полосами сверху и снизу
+	c := New(500, 400)
+	c.SetViewport(800, 0, 800, 900, 1) // Правая половина разделенного экрана
+	c.SetZoom(1.25)
+
+	// Точка мира на экране окна, как ее сообщит ebiten
+	sx, sy := v.ToScreen(c.WorldToScreen(520, 380))
+	wx, wy := c.ScreenToWorld(v.ToVirtual(sx, sy))
+	if !near(wx, 520) || !near(wy, 380) {
+		t.Fatalf("указатель в мире = (%v, %v), ожидалось (520, 380)", wx, wy)
+	}
+}
+
+func TestClampToBounds(t *testing.T) {
+	c := New(0, 0)
+	c.Bounds = Rect{0, 0, 1000, 1000}
+	c.SetViewport(0, 0, 200, 100, 1)
+
+	c.Snap(-500, 2000)
+	if !near(c.X, 100) || !near(c.Y, 950) {
+		t.Fatalf("камера у края = (%v, %v), ожидалось (100, 950)", c.X, c.Y)
+	}
+	v := c.Visible()
+	if v.MinX < 0 || v.MaxY > 1000 {
+		t.Fatalf("обзор вышел за границы: %+v", v)
+	}
+
+	// Карта меньше обзора: показывается по центру
+	c.Bounds = Rect{0, 0, 100, 50}
+	c.Snap(500, 500)
+	if !near(c.X, 50) || !near(c.Y, 25) {
+		t.Fatalf("маленькая карта = (%v, %v), ожидалось (50, 25)", c.X, c.Y)
+	}
+}
+
+func TestFollowIsFrameRateIndependent(t *testing.T) {
+	a, b := New(0, 0), New(0, 0)
+	for i := 0; i < 60; i++ {
+		a.Follow(100, 0, 1.0/60)
+	}
+	for i := 0; i < 120; i++ {
+		b.Follow(100, 0, 1.0/120)
+	}
+	if math.Abs(a.X-b.X) > 1e-6 {
+		t.Fatalf("за секунду при 60 и 120 тиках: %v и %v", a.X, b.X)
+	}
+	if a.X <= 0 || a.X >= 100 {
+		t.Fatalf("камера должна быть между началом и целью: %v", a.X)
+	}
+}
+
+func TestZoomLimits(t *testing.T) {
+	c := New(0, 0)
+	c.ZoomBy(100)
+	if c.Zoom != c.MaxZoom {
+		t.Fatalf("zoom = %v, ожидалось %v", c.Zoom, c.MaxZoom)
+	}
+	c.SetZoom(0)
+	if c.Zoom != c.MinZoom {
+		t.Fatalf("zoom = %v, ожидалось %v", c.Zoom, c.MinZoom)
+	}
+}
+
+func TestShakeDecays(t *testing.T) {
+	c := New(0, 0)
+	c.SetViewport(0, 0, 100, 100, 1)
+	c.Shake(0.8)
+	moved := false
+	for i := 0; i < 10; i++ {
+		c.Update(1.0 / 60)
+		if x, y := c.WorldToScreen(0, 0); !near(x, 50) || !near(y, 50) {
+			moved = true
+		}
+	}
+	if !moved {
+		t.Fatal("тряска не сместила обзор")
+	}
+	for i := 0; i < 120; i++ {
+		c.Update(1.0 / 60)
+	}
+	if x, y := c.WorldToScreen(0, 0); !near(x, 50) || !near(y, 50) {
+		t.Fatalf("тряска не затухла: (%v, %v)", x, y)
+	}
+}
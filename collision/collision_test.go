@@ -0,0 +1,102 @@
+package collision
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestPenetration(t *testing.T) {
+	cases := []struct {
+		name   string
+		a      Shape
+		ax, ay float64
+		b      Shape
+		bx, by float64
+		dx, dy float64
+		hit    bool
+	}{
+		{"прямоугольники врозь", Box(0, 0, 10, 10), 0, 0, Box(0, 0, 10, 10), 20, 0, 0, 0, false},
+		{"касание не столкновение", Box(0, 0, 10, 10), 0, 0, Box(0, 0, 10, 10), 10, 0, 0, 0, false},
+		{"прямоугольник слева", Box(0, 0, 10, 10), 0, 0, Box(0, 0, 10, 10), 8, 1, -2, 0, true},
+		{"прямоугольник снизу", Box(0, 0, 10, 10), 0, 7, Box(0, 0, 10, 10), 1, 0, 0, 3, true},
+		{"круг у стороны", Circle(0, 0, 5), 12, 5, Box(0, 0, 10, 10), 0, 0, 3, 0, true},
+		{"круг у угла врозь", Circle(0, 0, 5), 14, 14, Box(0, 0, 10, 10), 0, 0, 0, 0, false},
+		{"прямоугольник и круг", Box(0, 0, 10, 10), 0, 0, Circle(0, 0, 5), 12, 5, -3, 0, true},
+		{"круги", Circle(0, 0, 5), 0, 0, Circle(0, 0, 5), 8, 0, -2, 0, true},
+	}
+	for _, c := range cases {
+		dx, dy, hit := Penetration(c.a, c.ax, c.ay, c.b, c.bx, c.by)
+		if hit != c.hit || !near(dx, c.dx) || !near(dy, c.dy) {
+			t.Errorf("%s: (%v, %v, %v), ожидалось (%v, %v, %v)", c.name, dx, dy, hit, c.dx, c.dy, c.hit)
+		}
+	}
+}
+
+func TestSpatialHash(t *testing.T) {
+	h := NewSpatialHash(16)
+	h.Insert(1, AABB{0, 0, 10, 10})
+	h.Insert(2, AABB{100, 100, 140, 140})
+	h.Insert(3, AABB{-50, -50, 5, 5})
+
+	got := h.Query(AABB{2, 2, 20, 20})
+	if len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("Query = %v, ожидалось [1 3]", got)
+	}
+
+	h.Insert(1, AABB{200, 200, 210, 210}) // Перемещение
+	h.Remove(3)
+	if got := h.Query(AABB{2, 2, 20, 20}); len(got) != 0 {
+		t.Fatalf("после перемещения и удаления Query = %v", got)
+	}
+	if got := h.Query(AABB{120, 120, 205, 205}); len(got) != 2 {
+		t.Fatalf("Query = %v, ожидалось [1 2]", got)
+	}
+}
+
+func TestMoveSlidesAlongWall(t *testing.T) {
+	w := NewWorld(32)
+	w.AddBox(AABB{0, 100, 320, 132}) // Пол из нескольких тайлов одной строкой
+	player := w.Add(50, 80, Box(-10, -14, 20, 28))
+
+	// Наискосок в пол: по X движение продолжается, по Y упирается
+	x, y := w.Move(player, 10, 10)
+	if !near(x, 60) || !near(y, 86) {
+		t.Fatalf("позиция (%v, %v), ожидалось (60, 86)", x, y)
+	}
+	if len(w.Overlapping(player)) != 0 {
+		t.Fatal("тело осталось внутри препятствия")
+	}
+}
+
+func TestMoveDoesNotTunnel(t *testing.T) {
+	w := NewWorld(32)
+	w.AddBox(AABB{100, 0, 104, 200}) // Тонкая стена
+	player := w.Add(50, 100, Circle(0, 0, 8))
+
+	x, _ := w.Move(player, 200, 0)
+	if x > 92+1e-9 {
+		t.Fatalf("тело прошло сквозь стену: x = %v", x)
+	}
+}
+
+func TestPlayersPushApart(t *testing.T) {
+	w := NewWorld(32)
+	other := w.Add(40, 0, Box(-10, -14, 20, 28))
+	player := w.Add(0, 0, Box(-10, -14, 20, 28))
+
+	x, _ := w.Move(player, 30, 0)
+	if !near(x, 20) {
+		t.Fatalf("игрок зашел в другого: x = %v, ожидалось 20", x)
+	}
+
+	// Другой игрок перенесен сервером прямо в нас: следующий шаг выталкивает
+	w.SetPosition(other, 25, 0)
+	x, _ = w.Move(player, 0, 0)
+	if len(w.Overlapping(player)) != 0 {
+		t.Fatalf("игроки остались пересекающимися, x = %v", x)
+	}
+}
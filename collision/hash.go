@@ -0,0 +1,97 @@
+package collision
+
+import (
+	"math"
+	"sort"
+)
+
+// SpatialHash равномерная сетка ячеек для быстрого поиска тел рядом с
+// прямоугольником: проверяются только тела из тех же ячеек, а не все подряд
+type SpatialHash struct {
+	cellSize float64
+	cells    map[cell][]int
+	bounds   map[int]AABB
+}
+
+type cell struct{ x, y int }
+
+// NewSpatialHash создает сетку с ячейками cellSize x cellSize; удобный размер -
+// порядка самого крупного тела
+func NewSpatialHash(cellSize float64) *SpatialHash {
+	if cellSize <= 0 {
+		cellSize = 64
+	}
+	return &SpatialHash{
+		cellSize: cellSize,
+		cells:    make(map[cell][]int),
+		bounds:   make(map[int]AABB),
+	}
+}
+
+// Insert добавляет тело id с описанным прямоугольником b; повторная вставка
+// того же id заменяет прежний прямоугольник
+func (h *SpatialHash) Insert(id int, b AABB) {
+	if _, ok := h.bounds[id]; ok {
+		h.Remove(id)
+	}
+	h.bounds[id] = b
+	h.forCells(b, func(c cell) {
+		h.cells[c] = append(h.cells[c], id)
+	})
+}
+
+// Remove убирает тело id из сетки
+func (h *SpatialHash) Remove(id int) {
+	b, ok := h.bounds[id]
+	if !ok {
+		return
+	}
+	delete(h.bounds, id)
+	h.forCells(b, func(c cell) {
+		ids := h.cells[c]
+		for i, other := range ids {
+			if other == id {
+				ids = append(ids[:i], ids[i+1:]...)
+				break
+			}
+		}
+		if len(ids) == 0 {
+			delete(h.cells, c)
+		} else {
+			h.cells[c] = ids
+		}
+	})
+}
+
+// Query возвращает тела, чьи прямоугольники пересекают b, по возрастанию id,
+// чтобы порядок разрешения столкновений не зависел от обхода map
+func (h *SpatialHash) Query(b AABB) []int {
+	seen := make(map[int]bool)
+	var found []int
+	h.forCells(b, func(c cell) {
+		for _, id := range h.cells[c] {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if h.bounds[id].Overlaps(b) {
+				found = append(found, id)
+			}
+		}
+	})
+	sort.Ints(found)
+	return found
+}
+
+// forCells вызывает fn для каждой ячейки, которую задевает прямоугольник
+func (h *SpatialHash) forCells(b AABB, fn func(cell)) {
+	minX := int(math.Floor(b.MinX / h.cellSize))
+	minY := int(math.Floor(b.MinY / h.cellSize))
+	maxX := int(math.Floor(b.MaxX / h.cellSize))
+	maxY := int(math.Floor(b.MaxY / h.cellSize))
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			fn(cell{x, y})
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package collision проверяет столкновения прямоугольников и кругов и
+// сдвигает тела так, чтобы они скользили вдоль препятствий, а не проходили
+// сквозь них. Пакет не зависит от ebiten: одни и те же правила использует
+// предсказание движения на клиенте и сервер.
+package collision
+
+import "math"
+
+// AABB прямоугольник, стороны которого параллельны осям
+type AABB struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// Overlaps сообщает, пересекаются ли прямоугольники; касание сторонами не считается
+func (a AABB) Overlaps(b AABB) bool {
+	return a.MinX < b.MaxX && b.MinX < a.MaxX && a.MinY < b.MaxY && b.MinY < a.MaxY
+}
+
+// Translate возвращает прямоугольник, сдвинутый на (dx, dy)
+func (a AABB) Translate(dx, dy float64) AABB {
+	return AABB{a.MinX + dx, a.MinY + dy, a.MaxX + dx, a.MaxY + dy}
+}
+
+// Kind вид фигуры
+type Kind int
+
+const (
+	KindBox Kind = iota
+	KindCircle
+)
+
+// Shape фигура тела относительно его позиции. У прямоугольника X, Y - левый
+// верхний угол, у круга - центр.
+type Shape struct {
+	Kind Kind
+	X, Y float64
+	W, H float64 // Размер прямоугольника
+	R    float64 // Радиус круга
+}
+
+// Box прямоугольник w x h с левым верхним углом (x, y) относительно позиции тела
+func Box(x, y, w, h float64) Shape {
+	return Shape{Kind: KindBox, X: x, Y: y, W: w, H: h}
+}
+
+// Circle круг радиуса r с центром (x, y) относительно позиции тела
+func Circle(x, y, r float64) Shape {
+	return Shape{Kind: KindCircle, X: x, Y: y, R: r}
+}
+
+// Bounds возвращает описанный прямоугольник фигуры тела в точке (x, y)
+func (s Shape) Bounds(x, y float64) AABB {
+	if s.Kind == KindCircle {
+		cx, cy := x+s.X, y+s.Y
+		return AABB{cx - s.R, cy - s.R, cx + s.R, cy + s.R}
+	}
+	return AABB{x + s.X, y + s.Y, x + s.X + s.W, y + s.Y + s.H}
+}
+
+// Penetration возвращает наименьший сдвиг, выталкивающий фигуру a в точке
+// (ax, ay) из фигуры b в точке (bx, by); ok = false, если они не пересекаются
+func Penetration(a Shape, ax, ay float64, b Shape, bx, by float64) (dx, dy float64, ok bool) {
+	switch {
+	case a.Kind == KindBox && b.Kind == KindBox:
+		return boxBox(a.Bounds(ax, ay), b.Bounds(bx, by))
+	case a.Kind == KindCircle && b.Kind == KindBox:
+		return circleBox(ax+a.X, ay+a.Y, a.R, b.Bounds(bx, by))
+	case a.Kind == KindBox && b.Kind == KindCircle:
+		dx, dy, ok = circleBox(bx+b.X, by+b.Y, b.R, a.Bounds(ax, ay))
+		return -dx, -dy, ok
+	default:
+		return circleCircle(ax+a.X, ay+a.Y, a.R, bx+b.X, by+b.Y, b.R)
+	}
+}
+
+// boxBox выталкивает a из b по оси с наименьшим перекрытием
+func boxBox(a, b AABB) (float64, float64, bool) {
+	if !a.Overlaps(b) {
+		return 0, 0, false
+	}
+	left := b.MinX - a.MaxX // Отрицательные: сдвиг влево или вверх
+	right := b.MaxX - a.MinX
+	up := b.MinY - a.MaxY
+	down := b.MaxY - a.MinY
+
+	dx := right
+	if -left < right {
+		dx = left
+	}
+	dy := down
+	if -up < down {
+		dy = up
+	}
+	if math.Abs(dx) < math.Abs(dy) {
+		return dx, 0, true
+	}
+	return 0, dy, true
+}
+
+// circleBox выталкивает круг из прямоугольника
+func circleBox(cx, cy, r float64, b AABB) (float64, float64, bool) {
+	// Ближайшая к центру точка прямоугольника
+	px := math.Max(b.MinX, math.Min(cx, b.MaxX))
+	py := math.Max(b.MinY, math.Min(cy, b.MaxY))
+	ddx, ddy := cx-px, cy-py
+	dist2 := ddx*ddx + ddy*ddy
+
+	if dist2 == 0 {
+		// Центр внутри прямоугольника: выталкиваем как описанный квадрат
+		return boxBox(AABB{cx - r, cy - r, cx + r, cy + r}, b)
+	}
+	if dist2 >= r*r {
+		return 0, 0, false
+	}
+	dist := math.Sqrt(dist2)
+	push := r - dist
+	return ddx / dist * push, ddy / dist * push, true
+}
+
+// circleCircle выталкивает первый круг из второго вдоль линии центров
+func circleCircle(ax, ay, ar, bx, by, br float64) (float64, float64, bool) {
+	ddx, ddy := ax-bx, ay-by
+	dist2 := ddx*ddx + ddy*ddy
+	sum := ar + br
+	if dist2 >= sum*sum {
+		return 0, 0, false
+	}
+	if dist2 == 0 {
+		// Центры совпали: направление не определено, выталкиваем вправо
+		return sum, 0, true
+	}
+	dist := math.Sqrt(dist2)
+	push := sum - dist
+	return ddx / dist * push, ddy / dist * push, true
+}
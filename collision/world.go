@@ -0,0 +1,154 @@
+package collision
+
+import "math"
+
+const (
+	maxResolveIterations = 4   // Сколько раз подряд выталкивать тело из соседей за один шаг
+	maxStepFraction      = 0.5 // Наибольший шаг за раз в долях наименьшего размера тела
+)
+
+// Body тело в мире: позиция и фигура относительно нее
+type Body struct {
+	X, Y  float64
+	Shape Shape
+}
+
+// World набор тел с сеткой для быстрого поиска соседей. Неподвижные
+// препятствия и другие игроки добавляются одинаково, двигается только
+// тело, переданное в Move.
+type World struct {
+	hash   *SpatialHash
+	bodies map[int]*Body
+	nextID int
+}
+
+// NewWorld создает пустой мир с ячейками сетки cellSize
+func NewWorld(cellSize float64) *World {
+	return &World{
+		hash:   NewSpatialHash(cellSize),
+		bodies: make(map[int]*Body),
+		nextID: 1,
+	}
+}
+
+// Add добавляет тело и возвращает его номер
+func (w *World) Add(x, y float64, shape Shape) int {
+	id := w.nextID
+	w.nextID++
+	w.bodies[id] = &Body{X: x, Y: y, Shape: shape}
+	w.hash.Insert(id, shape.Bounds(x, y))
+	return id
+}
+
+// AddBox добавляет неподвижный прямоугольник, например стену карты
+func (w *World) AddBox(b AABB) int {
+	return w.Add(b.MinX, b.MinY, Box(0, 0, b.MaxX-b.MinX, b.MaxY-b.MinY))
+}
+
+// Remove убирает тело из мира
+func (w *World) Remove(id int) {
+	delete(w.bodies, id)
+	w.hash.Remove(id)
+}
+
+// Body возвращает тело по номеру
+func (w *World) Body(id int) (Body, bool) {
+	b, ok := w.bodies[id]
+	if !ok {
+		return Body{}, false
+	}
+	return *b, true
+}
+
+// SetPosition переносит тело без проверки столкновений (например, по данным сервера)
+func (w *World) SetPosition(id int, x, y float64) {
+	b, ok := w.bodies[id]
+	if !ok {
+		return
+	}
+	b.X, b.Y = x, y
+	w.hash.Insert(id, b.Shape.Bounds(x, y))
+}
+
+// SetShape меняет фигуру тела, например при смене скина
+func (w *World) SetShape(id int, shape Shape) {
+	b, ok := w.bodies[id]
+	if !ok {
+		return
+	}
+	b.Shape = shape
+	w.hash.Insert(id, shape.Bounds(b.X, b.Y))
+}
+
+// Overlapping возвращает тела, с которыми пересекается тело id, по возрастанию номера
+func (w *World) Overlapping(id int) []int {
+	b, ok := w.bodies[id]
+	if !ok {
+		return nil
+	}
+	var found []int
+	for _, other := range w.hash.Query(b.Shape.Bounds(b.X, b.Y)) {
+		if other == id {
+			continue
+		}
+		o := w.bodies[other]
+		if _, _, hit := Penetration(b.Shape, b.X, b.Y, o.Shape, o.X, o.Y); hit {
+			found = append(found, other)
+		}
+	}
+	return found
+}
+
+// Move сдвигает тело id на (dx, dy) и возвращает новую позицию. Движение
+// идет сначала по X, потом по Y, а после каждого шага тело выталкивается
+// из препятствий, поэтому при движении наискосок в стену тело скользит
+// вдоль нее. Длинный шаг делится на части, чтобы не проскочить тонкую стену.
+func (w *World) Move(id int, dx, dy float64) (float64, float64) {
+	b, ok := w.bodies[id]
+	if !ok {
+		return 0, 0
+	}
+
+	steps := 1
+	if limit := w.maxStep(b.Shape); limit > 0 {
+		steps = max(1, int(math.Ceil(math.Max(math.Abs(dx), math.Abs(dy))/limit)))
+	}
+	for i := 0; i < steps; i++ {
+		b.X += dx / float64(steps)
+		w.resolve(id, b)
+		b.Y += dy / float64(steps)
+		w.resolve(id, b)
+	}
+	w.hash.Insert(id, b.Shape.Bounds(b.X, b.Y))
+	return b.X, b.Y
+}
+
+// resolve выталкивает тело из всех пересекающихся соседей
+func (w *World) resolve(id int, b *Body) {
+	for i := 0; i < maxResolveIterations; i++ {
+		moved := false
+		for _, other := range w.hash.Query(b.Shape.Bounds(b.X, b.Y)) {
+			if other == id {
+				continue
+			}
+			o := w.bodies[other]
+			if pushX, pushY, hit := Penetration(b.Shape, b.X, b.Y, o.Shape, o.X, o.Y); hit {
+				b.X += pushX
+				b.Y += pushY
+				moved = true
+			}
+		}
+		if !moved {
+			return
+		}
+	}
+}
+
+// maxStep возвращает наибольший безопасный шаг для фигуры
+func (w *World) maxStep(s Shape) float64 {
+	size := 2 * s.R
+	if s.Kind == KindBox {
+		size = math.Min(s.W, s.H)
+	}
+	return size * maxStepFraction
+}
@@ -0,0 +1,94 @@
+// Package config хранит настройки игры в JSON-файле и применяет их к окну ebiten.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// DefaultPath файл настроек по умолчанию, относительно рабочего каталога
+const DefaultPath = "settings.json"
+
+// Settings настройки окна и графики
+type Settings struct {
+	WindowWidth  int     `json:"windowWidth"`
+	WindowHeight int     `json:"windowHeight"`
+	Fullscreen   bool    `json:"fullscreen"`
+	VSync        bool    `json:"vsync"`
+	TPS          int     `json:"tps"`     // Тиков логики в секунду
+	FPS          int     `json:"fps"`     // Наибольшее число кадров в секунду (0 - без ограничения)
+	UIScale      float64 `json:"uiScale"` // Множитель размера окон и кнопок интерфейса
+}
+
+// Default настройки, совпадающие с прежним поведением игры
+func Default() Settings {
+	return Settings{
+		WindowWidth:  1600,
+		WindowHeight: 900,
+		VSync:        true,
+		TPS:          ebiten.DefaultTPS,
+		UIScale:      1,
+	}
+}
+
+// Load читает настройки; отсутствующий файл дает настройки по умолчанию.
+// Поля, которых нет в файле, тоже берутся по умолчанию.
+func Load(path string) (Settings, error) {
+	s := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("чтение настроек: %w", err)
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+	s.normalize()
+	return s, nil
+}
+
+// Save записывает настройки в файл
+func (s Settings) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("запись настроек: %w", err)
+	}
+	return nil
+}
+
+// Apply применяет настройки окна и частоты обновления.
+// Ограничение FPS выдерживает сама игра при отрисовке кадра.
+func (s Settings) Apply() {
+	ebiten.SetWindowSize(s.WindowWidth, s.WindowHeight)
+	ebiten.SetFullscreen(s.Fullscreen)
+	ebiten.SetVsyncEnabled(s.VSync)
+	ebiten.SetTPS(s.TPS)
+}
+
+// normalize исправляет значения, с которыми игра не сможет работать.
+// TPS всегда положительный: ebiten.SyncWithFPS сделал бы длительность тика неопределенной.
+func (s *Settings) normalize() {
+	d := Default()
+	if s.WindowWidth <= 0 || s.WindowHeight <= 0 {
+		s.WindowWidth, s.WindowHeight = d.WindowWidth, d.WindowHeight
+	}
+	if s.TPS <= 0 {
+		s.TPS = d.TPS
+	}
+	if s.FPS < 0 {
+		s.FPS = d.FPS
+	}
+	if s.UIScale <= 0 {
+		s.UIScale = d.UIScale
+	}
+}
@@ -14,4 +14,95 @@ const (
 
 	ActionConfirm
 	ActionRestart
+
+	ActionPull
+	ActionPush
+
+	ActionSplitScreen // Переключение игры вдвоем в меню
+
+	// Редактирование текста в полях ввода
+	ActionTextBackspace
+	ActionTextDelete
+	ActionTextLeft
+	ActionTextRight
+	ActionTextHome
+	ActionTextEnd
+	ActionTextSelect // Удерживается вместе со стрелками для выделения
+	ActionTextSelectAll
+	ActionTextCopy
+	ActionTextCut
+	ActionTextPaste
+
+	ActionPause
+
+	// Приближение камеры на уровне
+	ActionZoomIn
+	ActionZoomOut
+
+	// Нажатие левой кнопки мыши или касание экрана,
+	// позиция указателя берется из Source.PointerPos
+	ActionPointer
+
+	// Переход на уровень по цифровой клавише на отладочных уровнях
+	ActionLevel1
+	ActionLevel2
+
+	actionCount // Количество действий, должно оставаться последним
 )
+
+// actionNames имена действий в файлах записи ввода. Записи хранят имена, а не
+// номера, поэтому константы можно переставлять, а имена менять нельзя.
+var actionNames = [actionCount]string{
+	ActionMoveRight: "move_right",
+	ActionMoveDown:  "move_down",
+	ActionMoveLeft:  "move_left",
+	ActionMoveUp:    "move_up",
+
+	ActionConfirm: "confirm",
+	ActionRestart: "restart",
+
+	ActionPull: "pull",
+	ActionPush: "push",
+
+	ActionSplitScreen: "split_screen",
+
+	ActionTextBackspace: "text_backspace",
+	ActionTextDelete:    "text_delete",
+	ActionTextLeft:      "text_left",
+	ActionTextRight:     "text_right",
+	ActionTextHome:      "text_home",
+	ActionTextEnd:       "text_end",
+	ActionTextSelect:    "text_select",
+	ActionTextSelectAll: "text_select_all",
+	ActionTextCopy:      "text_copy",
+	ActionTextCut:       "text_cut",
+	ActionTextPaste:     "text_paste",
+
+	ActionPause: "pause",
+
+	ActionZoomIn:  "zoom_in",
+	ActionZoomOut: "zoom_out",
+
+	ActionPointer: "pointer",
+
+	ActionLevel1: "level1",
+	ActionLevel2: "level2",
+}
+
+// ActionName возвращает имя действия, под которым оно сохраняется в записи ввода
+func ActionName(action input.Action) string {
+	if action < actionCount {
+		return actionNames[action]
+	}
+	return ""
+}
+
+// ActionByName возвращает действие по имени из записи ввода
+func ActionByName(name string) (input.Action, bool) {
+	for a := ActionNone + 1; a < actionCount; a++ {
+		if actionNames[a] == name {
+			return a, true
+		}
+	}
+	return ActionNone, false
+}
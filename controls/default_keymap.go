@@ -34,4 +34,33 @@ var DefaultKeymap = input.Keymap{
 		input.KeyWithModifier(input.KeyR, input.ModControl),
 		input.KeyGamepadBack,
 	},
+
+	ActionPause: {
+		input.KeyEscape,
+		input.KeyGamepadStart,
+	},
+
+	ActionPull: {
+		input.KeyP,
+		input.KeyGamepadA,
+	},
+	ActionPush: {
+		input.KeyO,
+		input.KeyGamepadB,
+	},
+
+	ActionZoomIn: {
+		input.KeyEqual,
+		input.KeyWheelUp,
+		input.KeyGamepadR1,
+	},
+	ActionZoomOut: {
+		input.KeyMinus,
+		input.KeyWheelDown,
+		input.KeyGamepadL1,
+	},
+
+	ActionPointer: {
+		input.KeyMouseLeft,
+	},
 }
@@ -0,0 +1,34 @@
+package controls
+
+import (
+	input "github.com/quasilyte/ebitengine-input"
+)
+
+// TextKeymap раскладка для редактирования текста в полях ввода
+var TextKeymap = input.Keymap{
+	ActionTextBackspace: {input.KeyBackspace},
+	ActionTextDelete:    {input.KeyDelete},
+	ActionTextLeft:      {input.KeyLeft},
+	ActionTextRight:     {input.KeyRight},
+	ActionTextHome:      {input.KeyHome},
+	ActionTextEnd:       {input.KeyEnd},
+	ActionTextSelect:    {input.KeyShift},
+	ActionTextSelectAll: {input.KeyWithModifier(input.KeyA, input.ModControl)},
+	ActionTextCopy:      {input.KeyWithModifier(input.KeyC, input.ModControl)},
+	ActionTextCut:       {input.KeyWithModifier(input.KeyX, input.ModControl)},
+	ActionTextPaste: {
+		input.KeyWithModifier(input.KeyV, input.ModControl),
+		input.KeyWithModifier(input.KeyInsert, input.ModShift),
+	},
+}
+
+// MenuKeymap раскладка меню: навигация, ввод текста и служебные клавиши
+var MenuKeymap = input.MergeKeymaps(DefaultKeymap, TextKeymap, input.Keymap{
+	ActionSplitScreen: {input.KeyF2},
+})
+
+// LevelSelectKeymap раскладка отладочных уровней: переходы по цифровым клавишам
+var LevelSelectKeymap = input.MergeKeymaps(DefaultKeymap, input.Keymap{
+	ActionLevel1: {input.Key1},
+	ActionLevel2: {input.Key2},
+})
@@ -0,0 +1,188 @@
+package controls
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// frame состояние одного источника ввода за один тик.
+// В файле записи каждая строка - отдельный frame в формате JSON.
+// Тики без нажатых действий и без текста не записываются.
+type frame struct {
+	Channel int      `json:"ch"`                // Порядковый номер источника в сессии
+	Tick    int      `json:"tick"`              // Номер тика от создания источника
+	Pressed []string `json:"pressed,omitempty"` // Имена нажатых действий (см. ActionName)
+	Text    string   `json:"text,omitempty"`
+	X       float64  `json:"x,omitempty"` // Позиция указателя
+	Y       float64  `json:"y,omitempty"`
+}
+
+// Recording записывает ввод всех источников сессии в файл
+type Recording struct {
+	mu       sync.Mutex
+	file     *os.File
+	enc      *json.Encoder
+	channels int
+}
+
+// NewRecording создает файл записи, существующий файл перезаписывается
+func NewRecording(path string) (*Recording, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("создание файла записи ввода: %w", err)
+	}
+	return &Recording{file: file, enc: json.NewEncoder(file)}, nil
+}
+
+// Wrap оборачивает источник так, что каждый его тик попадает в запись
+func (r *Recording) Wrap(src Source) Source {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ch := r.channels
+	r.channels++
+	return &recorder{Source: src, rec: r, channel: ch}
+}
+
+// Close дописывает запись и закрывает файл
+func (r *Recording) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+func (r *Recording) write(f frame) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(f)
+}
+
+// recorder источник, пропускающий ввод без изменений и записывающий его
+type recorder struct {
+	Source
+	rec     *Recording
+	channel int
+	tick    int
+	failed  bool // После ошибки записи больше не пытаемся писать
+}
+
+func (r *recorder) Update() {
+	r.Source.Update()
+	r.tick++
+
+	var pressed actionSet
+	for a := ActionNone + 1; a < actionCount; a++ {
+		if r.Source.ActionIsPressed(a) {
+			pressed.add(a)
+		}
+	}
+	text := r.Source.InputText()
+	if pressed == 0 && text == "" || r.failed {
+		return
+	}
+
+	var names []string
+	for _, a := range pressed.actions() {
+		names = append(names, ActionName(a))
+	}
+	x, y := r.Source.PointerPos()
+	err := r.rec.write(frame{Channel: r.channel, Tick: r.tick, Pressed: names, Text: text, X: x, Y: y})
+	if err != nil {
+		r.failed = true
+		slog.Error("Ошибка записи ввода", "err", err)
+	}
+}
+
+// Replay загруженная запись ввода для воспроизведения
+type Replay struct {
+	frames   map[int]map[int]frame // Канал -> тик -> состояние
+	lastTick map[int]int           // Последний записанный тик каждого канала
+	sources  []*playback           // Созданные источники по порядку каналов
+}
+
+// LoadReplay читает файл, созданный Recording
+func LoadReplay(path string) (*Replay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("открытие файла записи ввода: %w", err)
+	}
+	defer file.Close()
+
+	r := &Replay{
+		frames:   make(map[int]map[int]frame),
+		lastTick: make(map[int]int),
+	}
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var f frame
+		if err := json.Unmarshal(scanner.Bytes(), &f); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		for _, name := range f.Pressed {
+			if _, ok := ActionByName(name); !ok {
+				return nil, fmt.Errorf("%s:%d: неизвестное действие %q", path, line, name)
+			}
+		}
+		if r.frames[f.Channel] == nil {
+			r.frames[f.Channel] = make(map[int]frame)
+		}
+		r.frames[f.Channel][f.Tick] = f
+		if f.Tick > r.lastTick[f.Channel] {
+			r.lastTick[f.Channel] = f.Tick
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("чтение файла записи ввода: %w", err)
+	}
+	return r, nil
+}
+
+// NewSource создает источник, воспроизводящий следующий по порядку канал записи.
+// Уровни должны создавать источники в том же порядке, что и при записи.
+func (r *Replay) NewSource() Source {
+	ch := len(r.sources)
+	p := &playback{frames: r.frames[ch], last: r.lastTick[ch]}
+	r.sources = append(r.sources, p)
+	return p
+}
+
+// Finished сообщает, что все каналы записи воспроизведены до конца
+func (r *Replay) Finished() bool {
+	if len(r.sources) < len(r.frames) {
+		return false // Часть записанных источников еще не создана
+	}
+	for _, p := range r.sources {
+		if p.tick < p.last {
+			return false
+		}
+	}
+	return true
+}
+
+// playback источник, выдающий записанные состояния вместо живого ввода
+type playback struct {
+	tickState
+	frames map[int]frame
+	tick   int
+	last   int
+}
+
+func (p *playback) Update() {
+	p.tick++
+	f := p.frames[p.tick]
+
+	var pressed actionSet
+	for _, name := range f.Pressed {
+		a, _ := ActionByName(name) // Имена проверены при загрузке
+		pressed.add(a)
+	}
+	p.advance(pressed, f.Text, f.X, f.Y)
+}
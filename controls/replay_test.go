@@ -0,0 +1,164 @@
+package controls
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	input "github.com/quasilyte/ebitengine-input"
+)
+
+// scriptedTick ввод одного тика для scripted
+type scriptedTick struct {
+	pressed []input.Action
+	text    string
+	x, y    float64
+}
+
+// scripted источник, выдающий заранее заданный ввод вместо клавиатуры;
+// после конца сценария ничего не нажато
+type scripted struct {
+	tickState
+	ticks []scriptedTick
+	n     int
+}
+
+func (s *scripted) Update() {
+	var t scriptedTick
+	if s.n < len(s.ticks) {
+		t = s.ticks[s.n]
+	}
+	s.n++
+	var pressed actionSet
+	for _, a := range t.pressed {
+		pressed.add(a)
+	}
+	s.advance(pressed, t.text, t.x, t.y)
+}
+
+// snapshot то, что уровень видит в источнике за один тик
+type snapshot struct {
+	pressed, just actionSet
+	text          string
+	x, y          float64
+}
+
+func snap(src Source) snapshot {
+	var s snapshot
+	for a := ActionNone + 1; a < actionCount; a++ {
+		if src.ActionIsPressed(a) {
+			s.pressed.add(a)
+		}
+		if src.ActionIsJustPressed(a) {
+			s.just.add(a)
+		}
+	}
+	s.text = src.InputText()
+	s.x, s.y = src.PointerPos()
+	return s
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	scripts := [][]scriptedTick{
+		{
+			{},
+			{pressed: []input.Action{ActionMoveRight}},
+			{pressed: []input.Action{ActionMoveRight, ActionPull}},
+			{pressed: []input.Action{ActionMoveRight}, text: "ёж"},
+			{},
+			{pressed: []input.Action{ActionPointer}, x: 120, y: 45.5},
+			{pressed: []input.Action{ActionPointer}, x: 130, y: 50},
+			{},
+		},
+		{
+			{pressed: []input.Action{ActionMoveUp}}, // Зажато с открытия сцены: не "только что"
+			{pressed: []input.Action{ActionMoveUp, ActionPush}},
+		},
+	}
+	const ticks = 10 // Дольше сценариев, чтобы проверить конец записи
+
+	path := filepath.Join(t.TempDir(), "input.jsonl")
+	rec, err := NewRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var live []Source
+	for _, script := range scripts {
+		live = append(live, rec.Wrap(&scripted{ticks: script}))
+	}
+	want := make([][]snapshot, len(live))
+	for tick := 0; tick < ticks; tick++ {
+		for ch, src := range live {
+			src.Update()
+			want[ch] = append(want[ch], snap(src))
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := LoadReplay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var played []Source
+	for range scripts {
+		played = append(played, replay.NewSource())
+	}
+	for tick := 0; tick < ticks; tick++ {
+		for ch, src := range played {
+			src.Update()
+			got, exp := snap(src), want[ch][tick]
+			if exp.pressed == 0 && exp.text == "" {
+				// Пустые тики не записываются, позиция указателя в них не сохраняется
+				got.x, got.y, exp.x, exp.y = 0, 0, 0, 0
+			}
+			if got != exp {
+				t.Errorf("канал %d, тик %d: %+v, ожидалось %+v", ch, tick+1, got, exp)
+			}
+		}
+		// Последний непустой тик первого канала - седьмой
+		if done := replay.Finished(); done != (tick+1 >= 7) {
+			t.Errorf("тик %d: Finished = %v", tick+1, done)
+		}
+	}
+
+	// Проверяем сами фронты нажатий, а не только совпадение с живым вводом
+	first := want[0]
+	if !first[1].just.has(ActionMoveRight) || first[2].just.has(ActionMoveRight) || !first[2].just.has(ActionPull) {
+		t.Errorf("неверные фронты нажатий: %+v", first[:3])
+	}
+	if want[1][0].just.has(ActionMoveUp) || !want[1][1].just.has(ActionPush) {
+		t.Errorf("неверные фронты второго канала: %+v", want[1][:2])
+	}
+}
+
+func TestActionNames(t *testing.T) {
+	seen := make(map[string]input.Action)
+	for a := ActionNone + 1; a < actionCount; a++ {
+		name := ActionName(a)
+		if name == "" {
+			t.Fatalf("у действия %d нет имени для записи ввода", a)
+		}
+		if other, ok := seen[name]; ok {
+			t.Fatalf("имя %q у действий %d и %d", name, other, a)
+		}
+		seen[name] = a
+		if got, ok := ActionByName(name); !ok || got != a {
+			t.Fatalf("ActionByName(%q) = %d, %v", name, got, ok)
+		}
+	}
+}
+
+func TestReplayRejectsUnknownAction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.jsonl")
+	data := `{"ch":0,"tick":1,"pressed":["move_up"]}` + "\n" + `{"ch":0,"tick":2,"pressed":["jump"]}` + "\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadReplay(path)
+	if err == nil || !strings.Contains(err.Error(), "jump") {
+		t.Fatalf("ошибка = %v, ожидалось неизвестное действие jump", err)
+	}
+}
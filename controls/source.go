@@ -0,0 +1,189 @@
+package controls
+
+import (
+	"log/slog"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	input "github.com/quasilyte/ebitengine-input"
+)
+
+// Source состояние действий одного игрока за текущий тик.
+// Уровни читают ввод только через Source, поэтому живой ввод можно
+// записать в файл и затем воспроизвести вместо клавиатуры (см. Recording и Replay).
+type Source interface {
+	// Update снимает состояние за новый тик, вызывается игрой один раз за тик
+	Update()
+
+	ActionIsPressed(action input.Action) bool
+	ActionIsJustPressed(action input.Action) bool
+
+	// InputText возвращает текст, набранный или вставленный за текущий тик
+	InputText() string
+
+	// PointerPos возвращает позицию курсора мыши или касания в координатах экрана
+	PointerPos() (x, y float64)
+}
+
+// ClipboardReader источник текста для действия ActionTextPaste
+type ClipboardReader interface {
+	ReadText() (string, error)
+}
+
+// actionSet битовая маска нажатых действий
+type actionSet uint64
+
+func (s actionSet) has(action input.Action) bool {
+	return s&(1<<action) != 0
+}
+
+func (s *actionSet) add(action input.Action) {
+	*s |= 1 << action
+}
+
+// actions возвращает список нажатых действий по возрастанию
+func (s actionSet) actions() []input.Action {
+	var list []input.Action
+	for a := ActionNone + 1; a < actionCount; a++ {
+		if s.has(a) {
+			list = append(list, a)
+		}
+	}
+	return list
+}
+
+// tickState общее хранение состояния за тик для всех реализаций Source
+type tickState struct {
+	pressed actionSet
+	prev    actionSet // Состояние предыдущего тика для определения "только что нажато"
+	text    string
+	x, y    float64 // Позиция указателя
+	started bool    // Первый тик уже снят
+}
+
+func (s *tickState) advance(pressed actionSet, text string, x, y float64) {
+	s.prev = s.pressed
+	if !s.started {
+		// Клавиша, которой открыли сцену, еще может быть зажата;
+		// в первом тике нового источника она не должна считаться только что нажатой
+		s.prev = pressed
+		s.started = true
+	}
+	s.pressed = pressed
+	s.text = text
+	s.x, s.y = x, y
+}
+
+func (s *tickState) ActionIsPressed(action input.Action) bool {
+	return s.pressed.has(action)
+}
+
+func (s *tickState) ActionIsJustPressed(action input.Action) bool {
+	return s.pressed.has(action) && !s.prev.has(action)
+}
+
+func (s *tickState) InputText() string {
+	return s.text
+}
+
+func (s *tickState) PointerPos() (x, y float64) {
+	return s.x, s.y
+}
+
+// LiveSource живой ввод с клавиатуры, мыши и геймпада через ebitengine-input
+type LiveSource struct {
+	tickState
+	// PointerTransform переводит позицию указателя из координат экрана в координаты сцены
+	// (например, без полос по краям окна); nil - без преобразования
+	PointerTransform func(x, y float64) (float64, float64)
+
+	handler   *input.Handler
+	wheel     []WheelBinding
+	clipboard ClipboardReader
+	chars     []rune
+	touches   []ebiten.TouchID
+}
+
+// WheelBinding действие, которое нажимает прокрутка колеса мыши
+type WheelBinding struct {
+	Action input.Action
+	Up     bool // Прокрутка вверх, иначе вниз
+}
+
+// SplitWheel убирает из раскладки клавиши колеса мыши и возвращает их отдельно.
+// ebitengine-input проверяет удержание колеса как удержание клавиши клавиатуры
+// с тем же кодом, поэтому колесо LiveSource читает сам.
+func SplitWheel(keymap input.Keymap) (input.Keymap, []WheelBinding) {
+	keys := make(input.Keymap, len(keymap))
+	var wheel []WheelBinding
+	for action, list := range keymap {
+		for _, key := range list {
+			switch key {
+			case input.KeyWheelUp:
+				wheel = append(wheel, WheelBinding{Action: action, Up: true})
+			case input.KeyWheelDown:
+				wheel = append(wheel, WheelBinding{Action: action})
+			default:
+				keys[action] = append(keys[action], key)
+			}
+		}
+	}
+	return keys, wheel
+}
+
+// NewLiveSource создает источник живого ввода. В handler не должно быть клавиш колеса:
+// их передают в wheel (см. SplitWheel). clipboard может быть nil.
+func NewLiveSource(handler *input.Handler, wheel []WheelBinding, clipboard ClipboardReader) *LiveSource {
+	return &LiveSource{handler: handler, wheel: wheel, clipboard: clipboard}
+}
+
+// Handler возвращает обработчик ebitengine-input, на котором построен источник
+func (s *LiveSource) Handler() *input.Handler {
+	return s.handler
+}
+
+func (s *LiveSource) Update() {
+	var pressed actionSet
+	for a := ActionNone + 1; a < actionCount; a++ {
+		if s.handler.ActionIsPressed(a) {
+			pressed.add(a)
+		}
+	}
+
+	// Прокрутка колеса нажимает действие на тот тик, в который пришла
+	if _, wy := ebiten.Wheel(); wy != 0 {
+		for _, b := range s.wheel {
+			if b.Up == (wy > 0) {
+				pressed.add(b.Action)
+			}
+		}
+	}
+
+	// ebitengine-input сообщает о касании только после отпускания пальца,
+	// а виртуальному джойстику нужно удержание, поэтому касания читаем напрямую
+	pos := s.handler.CursorPos()
+	x, y := pos.X, pos.Y
+	s.touches = ebiten.AppendTouchIDs(s.touches[:0])
+	if len(s.touches) > 0 {
+		tx, ty := ebiten.TouchPosition(s.touches[0])
+		x, y = float64(tx), float64(ty)
+		pressed.add(ActionPointer)
+	}
+	if s.PointerTransform != nil {
+		x, y = s.PointerTransform(x, y)
+	}
+
+	s.chars = ebiten.AppendInputChars(s.chars[:0])
+	text := string(s.chars)
+
+	// Вставка из буфера обмена приходит как обычный набранный текст,
+	// чтобы запись сессии не зависела от содержимого буфера при воспроизведении
+	if pressed.has(ActionTextPaste) && !s.pressed.has(ActionTextPaste) && s.clipboard != nil {
+		clip, err := s.clipboard.ReadText()
+		if err != nil {
+			slog.Warn("Ошибка чтения буфера обмена", "err", err)
+		}
+		text += clip
+	}
+
+	s.advance(pressed, text, x, y)
+}
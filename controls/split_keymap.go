@@ -0,0 +1,43 @@
+package controls
+
+import (
+	input "github.com/quasilyte/ebitengine-input"
+)
+
+// Раскладки для локальной игры вдвоем на одном компьютере:
+// первый игрок управляет с клавиатуры, второй - с геймпада
+
+var KeyboardKeymap = input.Keymap{
+	ActionMoveRight: {input.KeyD},
+	ActionMoveDown:  {input.KeyS},
+	ActionMoveLeft:  {input.KeyA},
+	ActionMoveUp:    {input.KeyW},
+
+	ActionConfirm: {input.KeyEnter},
+	ActionRestart: {input.KeyWithModifier(input.KeyR, input.ModControl)},
+	ActionPause:   {input.KeyEscape},
+
+	ActionPull: {input.KeyP},
+	ActionPush: {input.KeyO},
+
+	ActionZoomIn:  {input.KeyEqual},
+	ActionZoomOut: {input.KeyMinus},
+
+	ActionPointer: {input.KeyMouseLeft},
+}
+
+var GamepadKeymap = input.Keymap{
+	ActionMoveRight: {input.KeyGamepadRight, input.KeyGamepadLStickRight},
+	ActionMoveDown:  {input.KeyGamepadDown, input.KeyGamepadLStickDown},
+	ActionMoveLeft:  {input.KeyGamepadLeft, input.KeyGamepadLStickLeft},
+	ActionMoveUp:    {input.KeyGamepadUp, input.KeyGamepadLStickUp},
+
+	ActionRestart: {input.KeyGamepadBack},
+	ActionPause:   {input.KeyGamepadStart}, // Только пауза, чтобы Start не подтверждал одновременно с ней
+
+	ActionPull: {input.KeyGamepadA},
+	ActionPush: {input.KeyGamepadB},
+
+	ActionZoomIn:  {input.KeyGamepadR1},
+	ActionZoomOut: {input.KeyGamepadL1},
+}
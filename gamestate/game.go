@@ -1,18 +1,32 @@
 package gamestate
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"image/color"
+	"log/slog"
 	"math"
+	"time"
 
+	"main.go/config"
+	"main.go/levels/errorscreen"
 	"main.go/levels/level1"
-	"main.go/levels/level5"
-	"main.go/levels/menu"
+	"main.go/resourses"
 	sprites "main.go/resourses/img"
+	"main.go/resourses/maps"
+	"main.go/scenes"
+	"main.go/view"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	input "github.com/quasilyte/ebitengine-input"
+	"main.go/controls"
+	"main.go/ui"
 )
 
+const loadingImagePath = "img/loadscreen.png"
+
 type GameState int
 
 const (
@@ -21,101 +35,463 @@ const (
 )
 
 type Game struct {
-	currentLevel ebiten.Game
-	nextLevel    int
+	stack        []*sceneEntry // Открытые сцены, верхняя получает ввод
+	pending      string        // Сцена, которая загружается или не загрузилась
+	pendingOp    stackOp       // Как загружаемая сцена попадет в стек
 	state        GameState
-	scale        float64
-	loadingImage *ebiten.Image // Поле для хранения изображения загрузочного экрана
-	playerName   string        // Поле для имени игрока
-	playerSkin   string        // Поле для скина игрока
+	loadingImage *ebiten.Image       // Поле для хранения изображения загрузочного экрана
+	playerName   string              // Поле для имени игрока
+	playerSkin   string              // Поле для скина игрока
+	secondName   string              // Имя второго локального игрока (пусто, если играет один)
+	secondSkin   string              // Скин второго локального игрока
+	input        input.System        // Система ввода, общая для всех уровней
+	sources      []controls.Source   // Источники ввода создаваемой сцены, затем переходят к ней
+	recording    *controls.Recording // Запись ввода в файл (nil, если не ведется)
+	replay       *controls.Replay    // Воспроизведение записи вместо живого ввода
+	replayDone   bool                // Конец записи уже обработан
+	joystick     bool                // Показывать экранный джойстик в Level1
+	watcher      *resourses.Watcher  // Отслеживание изменений ресурсов в режиме разработки
+	loader       *loader             // Фоновая загрузка следующей сцены
+	retryName    string              // Сцена, которую можно открыть заново с экрана ошибки
+	retryOp      stackOp             // Как открывать ее заново
+	transitions  transitioner        // Анимация смены сцен
+	settings     config.Settings     // Текущие настройки окна и графики
+	settingsPath string              // Файл, в который сохраняются настройки
+	server       string              // Адрес сервера для Level1
+	viewport     view.Viewport       // Область окна, занятая игрой
+	canvas       *ebiten.Image       // Виртуальный экран, на котором рисуются сцены
+	nextFrame    time.Time           // Раньше этого времени новый кадр не рисуется (ограничение FPS)
 }
 
 func NewGame() *Game {
-	// Загрузка изображения для экрана загрузки
-	loadingImage, _, err := ebitenutil.NewImageFromFile("gamestate/loadscreen.png")
+	// Изображение экрана загрузки нужно всегда, поэтому ссылка на него не освобождается.
+	// Без него игра работает, экран загрузки просто останется без картинки.
+	loadingImage, err := resourses.AcquireImage(loadingImagePath)
 	if err != nil {
-		panic(err) // Обработка ошибки загрузки изображения
+		slog.Warn("Ошибка загрузки изображения экрана загрузки", "err", err)
 	}
 
-	return &Game{
+	g := &Game{
 		loadingImage: loadingImage, // Инициализация изображения загрузочного экрана
+		transitions:  newTransitioner(),
+		settings:     config.Default(),
+		settingsPath: config.DefaultPath,
+		server:       level1.DefaultServerAddress,
 	}
+	g.input.Init(input.SystemConfig{DevicesEnabled: input.AnyDevice})
+	return g
 }
 func (g *Game) SetPlayerInfo(name, skin string) {
 	g.playerName = name
 	g.playerSkin = skin
 }
+
+// SetSecondPlayerInfo включает игру вдвоем на разделенном экране; пустое имя выключает ее
+func (g *Game) SetSecondPlayerInfo(name, skin string) {
+	g.secondName = name
+	g.secondSkin = skin
+}
+
+// UseSettings задает настройки, уже примененные к окну, и файл для их сохранения
+func (g *Game) UseSettings(path string, s config.Settings) {
+	g.settingsPath = path
+	g.settings = s
+}
+
+// Settings возвращает текущие настройки
+func (g *Game) Settings() config.Settings {
+	return g.settings
+}
+
+// ApplySettings применяет изменившиеся настройки сразу, без перезапуска
+func (g *Game) ApplySettings(s config.Settings) {
+	old := g.settings
+	g.settings = s
+	if s.WindowWidth != old.WindowWidth || s.WindowHeight != old.WindowHeight {
+		ebiten.SetWindowSize(s.WindowWidth, s.WindowHeight)
+	}
+	if s.Fullscreen != old.Fullscreen {
+		ebiten.SetFullscreen(s.Fullscreen)
+	}
+	if s.VSync != old.VSync {
+		ebiten.SetVsyncEnabled(s.VSync)
+	}
+	if s.TPS != old.TPS {
+		ebiten.SetTPS(s.TPS)
+	}
+}
+
+// SaveSettings записывает настройки в файл
+func (g *Game) SaveSettings() error {
+	return g.settings.Save(g.settingsPath)
+}
+
+// UIScale масштаб окон и кнопок интерфейса по настройке UI scale
+func (g *Game) UIScale() float64 {
+	return g.settings.UIScale
+}
+
+// SetServerAddress задает адрес сервера, к которому подключается Level1
+func (g *Game) SetServerAddress(addr string) {
+	g.server = addr
+}
+
+func (g *Game) ServerAddress() string {
+	return g.server
+}
+
+// SetVirtualJoystick включает экранный джойстик для движения касанием или мышью
+func (g *Game) SetVirtualJoystick(enabled bool) {
+	g.joystick = enabled
+}
+
+func (g *Game) VirtualJoystickEnabled() bool {
+	return g.joystick
+}
+
+// EnableHotReload включает перезагрузку измененных ресурсов из каталога dir без перезапуска
+func (g *Game) EnableHotReload(dir string) error {
+	watcher, err := resourses.Watch(dir, time.Second)
+	if err != nil {
+		return err
+	}
+	g.watcher = watcher
+	return nil
+}
+
+// reloadAssets применяет изменения ресурсов, найденные при опросе
+func (g *Game) reloadAssets(changed []string) {
+	slog.Info("Перезагрузка ресурсов", "files", changed)
+	for _, name := range changed {
+		if _, err := resourses.RefreshImage(name); err != nil {
+			slog.Error("Ошибка перезагрузки изображения", "err", err)
+		}
+	}
+	// При смене размера кэш заменяет текстуру, поэтому берем ее заново
+	if img, ok := resourses.CachedImage(loadingImagePath); ok {
+		g.loadingImage = img
+	}
+	if err := sprites.Reload(changed); err != nil {
+		slog.Error("Ошибка перезагрузки спрайтов", "err", err)
+	}
+	if err := maps.Reload(changed); err != nil {
+		slog.Error("Ошибка перезагрузки карты", "err", err)
+	}
+}
+
+// StartRecording включает запись ввода всех уровней в файл
+func (g *Game) StartRecording(path string) error {
+	rec, err := controls.NewRecording(path)
+	if err != nil {
+		return err
+	}
+	g.recording = rec
+	return nil
+}
+
+// StartReplay включает воспроизведение записанного ввода вместо живого
+func (g *Game) StartReplay(path string) error {
+	replay, err := controls.LoadReplay(path)
+	if err != nil {
+		return err
+	}
+	g.replay = replay
+	return nil
+}
+
+// Close закрывает все сцены, запоминает размер окна и завершает запись ввода, если она велась
+func (g *Game) Close() error {
+	g.exitAll()
+	if w, h := ebiten.WindowSize(); !g.settings.Fullscreen && w > 0 && h > 0 &&
+		(w != g.settings.WindowWidth || h != g.settings.WindowHeight) {
+		g.settings.WindowWidth, g.settings.WindowHeight = w, h
+		if err := g.SaveSettings(); err != nil {
+			slog.Error("Ошибка сохранения настроек", "err", err)
+		}
+	}
+	if g.watcher != nil {
+		g.watcher.Close()
+	}
+	if g.recording != nil {
+		return g.recording.Close()
+	}
+	return nil
+}
+
+// NewInputSource создает источник действий игрока с заданной раскладкой.
+// В режиме воспроизведения вместо живого ввода отдается запись.
+func (g *Game) NewInputSource(playerID uint8, keymap input.Keymap) controls.Source {
+	var src controls.Source
+	if g.replay != nil {
+		src = g.replay.NewSource()
+	} else {
+		keys, wheel := controls.SplitWheel(keymap)
+		live := controls.NewLiveSource(g.input.NewHandler(playerID, keys), wheel, ui.SystemClipboard{})
+		// Сцены получают указатель в координатах виртуального экрана
+		live.PointerTransform = g.ScreenToVirtual
+		src = live
+		if g.recording != nil {
+			src = g.recording.Wrap(src)
+		}
+	}
+	g.sources = append(g.sources, src)
+	return src
+}
+
+// SetTransition задает переход и его длительность для смены сцен без явного указания перехода
+func (g *Game) SetTransition(kind scenes.Transition, duration time.Duration) {
+	g.transitions.Default = kind
+	g.transitions.Duration = duration
+}
+
 func (g *Game) Update() error {
-	switch g.state {
-	case Playing:
-		if g.currentLevel != nil {
-			return g.currentLevel.Update()
+	g.transitions.update(time.Second / time.Duration(ebiten.TPS()))
+
+	// Пока уровень загружается в фоне, ресурсы и система ввода (новые обработчики
+	// и источники) принадлежат горутине загрузки
+	if g.state == Loading {
+		return g.updateLoading()
+	}
+
+	if g.watcher != nil {
+		select {
+		case changed := <-g.watcher.Changes:
+			g.reloadAssets(changed)
+		default:
 		}
-	case Loading:
-		g.loadNextLevel()
+	}
+
+	g.input.Update()
+	// Ввод получает только верхняя сцена
+	top := g.top()
+	if top == nil {
+		return nil
+	}
+	for _, src := range top.sources {
+		src.Update()
+	}
+	if g.replay != nil && !g.replayDone && g.replay.Finished() {
+		g.replayDone = true
+		slog.Info("Воспроизведение записи ввода завершено")
+	}
+
+	// Сцены под верхней (например, уровень под паузой) продолжают жить без ввода
+	for _, e := range g.stack[:len(g.stack)-1] {
+		h, ok := e.scene.(sceneBackground)
+		if !ok {
+			continue
+		}
+		if err := h.UpdateBackground(); err != nil {
+			g.transitions.start(g.transitions.Default)
+			g.closeFailed(e)
+			g.showError(err, e.name, opPush)
+			return nil
+		}
+	}
+
+	// Ошибка сцены (например, потеря связи с сервером) не завершает игру
+	if err := top.scene.Update(); err != nil {
+		if errors.Is(err, ebiten.Termination) {
+			return err
+		}
+		g.transitions.start(g.transitions.Default)
+		g.closeFailed(top)
+		g.showError(err, top.name, opPush)
 	}
 	return nil
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
+	g.limitFPS()
+
+	// Сцены рисуются на виртуальном экране постоянного размера, который затем
+	// растягивается на область игры; полосы по краям остаются черными
+	if g.canvas == nil {
+		g.canvas = ebiten.NewImage(view.VirtualWidth, view.VirtualHeight)
+	}
+	g.canvas.Clear()
+	g.transitions.draw(g.canvas, g.drawScenes)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(g.viewport.Scale, g.viewport.Scale)
+	op.GeoM.Translate(float64(g.viewport.Rect.Min.X), float64(g.viewport.Rect.Min.Y))
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(g.canvas, op)
+}
+
+// limitFPS придерживает отрисовку, чтобы кадров было не больше settings.FPS в секунду.
+// Логика при этом идет со своей частотой TPS: ebiten догоняет пропущенные тики.
+func (g *Game) limitFPS() {
+	if g.settings.FPS <= 0 {
+		return
+	}
+	now := time.Now()
+	if wait := g.nextFrame.Sub(now); wait > 0 {
+		time.Sleep(wait)
+		now = g.nextFrame
+	}
+	g.nextFrame = now.Add(time.Second / time.Duration(g.settings.FPS))
+}
+
+// drawScenes рисует текущее состояние игры; во время перехода - во внеэкранное изображение
+func (g *Game) drawScenes(screen *ebiten.Image) {
 	switch g.state {
 	case Playing:
-		if g.currentLevel != nil {
-			g.currentLevel.Draw(screen)
-		} else {
-			ebitenutil.DebugPrint(screen, "No Level Loaded")
+		if len(g.stack) == 0 {
+			ebitenutil.DebugPrint(screen, "No Scene Loaded")
+		}
+		// Сцены рисуются снизу вверх, чтобы окна поверх уровня его не скрывали
+		for _, e := range g.stack {
+			e.scene.Draw(screen)
 		}
 	case Loading:
-		if g.loadingImage != nil {
-			screenWidth := screen.Bounds().Dx()
-			screenHeight := screen.Bounds().Dy()
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Scale(float64(screenWidth)/float64(g.loadingImage.Bounds().Dx()), float64(screenHeight)/float64(g.loadingImage.Bounds().Dy()))
-			screen.DrawImage(g.loadingImage, op)
-			ebitenutil.DebugPrint(screen, "Loading...")
-		} else {
-			ebitenutil.DebugPrint(screen, "Loading...")
+		g.drawLoadingImage(screen)
+		progress, step := 0.0, ""
+		if g.loader != nil {
+			progress, step = g.loader.status()
 		}
+		g.drawProgressBar(screen, progress)
+		ebitenutil.DebugPrint(screen, "Loading...\n"+step)
+	}
+}
+
+// drawLoadingImage растягивает картинку экрана загрузки на весь экран
+func (g *Game) drawLoadingImage(screen *ebiten.Image) {
+	if g.loadingImage == nil {
+		return
 	}
+	screenWidth := screen.Bounds().Dx()
+	screenHeight := screen.Bounds().Dy()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(screenWidth)/float64(g.loadingImage.Bounds().Dx()), float64(screenHeight)/float64(g.loadingImage.Bounds().Dy()))
+	screen.DrawImage(g.loadingImage, op)
 }
 
+// drawProgressBar рисует полосу загрузки внизу экрана
+func (g *Game) drawProgressBar(screen *ebiten.Image, progress float64) {
+	bounds := screen.Bounds()
+	width := float32(bounds.Dx()) * 0.6
+	height := float32(20)
+	x := float32(bounds.Min.X) + (float32(bounds.Dx())-width)/2
+	y := float32(bounds.Min.Y) + float32(bounds.Dy())*0.85
+
+	vector.DrawFilledRect(screen, x, y, width, height, color.RGBA{0, 0, 0, 160}, false)
+	vector.DrawFilledRect(screen, x, y, width*float32(progress), height, color.RGBA{0, 160, 0, 255}, false)
+	vector.StrokeRect(screen, x, y, width, height, 2, color.White, false)
+}
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	baseWidth, baseHeight := 1600, 900
-	g.scale = math.Min(float64(outsideWidth)/float64(baseWidth), float64(outsideHeight)/float64(baseHeight))
-	screenWidth = int(float64(baseWidth) * g.scale)
-	screenHeight = int(float64(baseHeight) * g.scale)
-	return screenWidth, screenHeight
+	w, h := g.LayoutF(float64(outsideWidth), float64(outsideHeight))
+	return int(w), int(h)
+}
+
+// LayoutF делает экран размером с окно в физических пикселях (с учетом HiDPI),
+// а виртуальное разрешение вписывается в него с полосами по краям
+func (g *Game) LayoutF(outsideWidth, outsideHeight float64) (float64, float64) {
+	factor := ebiten.Monitor().DeviceScaleFactor()
+	w, h := math.Ceil(outsideWidth*factor), math.Ceil(outsideHeight*factor)
+	g.viewport = view.Fit(int(w), int(h), view.VirtualWidth, view.VirtualHeight)
+	return w, h
+}
+
+// ScreenToVirtual переводит точку экрана (например, позицию мыши от ebiten)
+// в координаты виртуального экрана, на котором рисуются сцены
+func (g *Game) ScreenToVirtual(x, y float64) (float64, float64) {
+	return g.viewport.ToVirtual(x, y)
 }
 
-func (g *Game) SwitchLevel(level int) {
-	g.nextLevel = level
-	g.state = Loading // Переход в состояние загрузки
+// updateLoading запускает фоновую загрузку и по ее завершении открывает сцену
+func (g *Game) updateLoading() error {
+	if g.loader == nil {
+		g.sources = nil
+		g.loader = newLoader(g.pending, g.pendingOp)
+		go g.load(g.loader)
+		return nil
+	}
+
+	result, ok := g.loader.poll()
+	if !ok {
+		return nil
+	}
+	l := g.loader
+	g.loader = nil
+	g.transitions.start(g.transitions.next)
+	if result.err != nil {
+		g.showError(result.err, l.name, l.op)
+		return nil
+	}
+
+	// Источники ввода, созданные сценой в горутине загрузки, переходят к ней
+	g.install(&sceneEntry{name: l.name, scene: result.scene, assets: result.assets, sources: g.sources}, l.op)
+	g.sources = nil
+	return nil
 }
 
-func (g *Game) loadNextLevel() {
+// load выполняется в горутине: захватывает ресурсы сцены и создает ее
+func (g *Game) load(l *loader) {
+	spec, ok := registry[l.name]
+	if !ok {
+		l.done <- loadResult{err: fmt.Errorf("неизвестная сцена %q", l.name)}
+		return
+	}
 
-	switch g.nextLevel {
-	case 1:
-		if err := sprites.LoadSprites(); err != nil {
-			log.Fatal("Ошибка загрузки спрайтов:", err)
+	steps := float64(len(spec.Assets) + 1)
+	for i, name := range spec.Assets {
+		l.report(float64(i)/steps, "Loading "+name)
+		if err := resourses.AcquireBundles(name); err != nil {
+			resourses.ReleaseBundles(spec.Assets[:i]...)
+			l.done <- loadResult{err: err}
+			return
 		}
-		g.currentLevel = level1.New(g, g.playerName, g.playerSkin)
-	case 2:
-		if err := sprites.LoadSprites(); err != nil {
-			log.Fatal("Ошибка загрузки спрайтов:", err)
+	}
+
+	step := spec.Step
+	if step == "" {
+		step = "Building " + l.name
+	}
+	l.report(float64(len(spec.Assets))/steps, step)
+	scene, err := spec.New(g)
+	if err != nil {
+		resourses.ReleaseBundles(spec.Assets...)
+		l.done <- loadResult{err: err}
+		return
+	}
+	l.report(1, "Done")
+	l.done <- loadResult{scene: scene, assets: spec.Assets}
+}
+
+// showError открывает экран ошибки поверх текущих сцен вместо аварийного завершения.
+// С него можно повторить открытие сцены name тем же способом op или вернуться в меню.
+func (g *Game) showError(err error, name string, op stackOp) {
+	slog.Error("Ошибка сцены", "scene", name, "err", err)
+	g.retryName, g.retryOp = name, op
+
+	g.sources = nil // Источники, созданные неудавшейся сценой, больше не нужны
+	screen := errorscreen.New(g, err, name != scenes.Menu)
+	g.install(&sceneEntry{name: scenes.Error, scene: screen, sources: g.sources}, opPush)
+	g.sources = nil
+}
+
+// closeFailed закрывает сцену, завершившуюся ошибкой, вместе со сценами над ней.
+// Сцена освобождает свои ресурсы (например, сетевые порты) еще до экрана ошибки,
+// иначе повтор открыл бы ее вторую копию рядом с первой.
+func (g *Game) closeFailed(e *sceneEntry) {
+	for len(g.stack) > 0 {
+		top := g.top()
+		g.stack = g.stack[:len(g.stack)-1]
+		g.exitScene(top)
+		if top == e {
+			break
 		}
-		g.currentLevel = menu.New(g)
-		if err := sprites.LoadSprites(); err != nil {
-			log.Fatal("Ошибка загрузки спрайтов:", err)
+	}
+	if top := g.top(); top != nil {
+		if h, ok := top.scene.(sceneResumer); ok {
+			h.OnResume()
 		}
-	case 5:
-		g.currentLevel = level5.New(g)
-	default:
-		g.currentLevel = nil
 	}
-	g.state = Playing
 }
 
-func (g *Game) GetScale() float64 {
-	return g.scale
+// RetryScene закрывает экран ошибки и снова открывает сцену, на которой она случилась
+func (g *Game) RetryScene() {
+	g.PopScene()
+	g.requestScene(g.retryName, g.retryOp, g.transitions.Default)
 }
@@ -0,0 +1,70 @@
+package gamestate
+
+import (
+	"sync"
+	"time"
+)
+
+// minLoadingTime минимальное время показа экрана загрузки, чтобы он не мелькал
+const minLoadingTime = 500 * time.Millisecond
+
+// loadResult итог фоновой загрузки сцены
+type loadResult struct {
+	scene  Scene
+	assets []string // Захваченные сценой группы ресурсов
+	err    error
+}
+
+// loader загружает сцену в отдельной горутине и сообщает о ходе загрузки.
+// Прогресс читается из Draw, поэтому защищен мьютексом; результат приходит через done.
+type loader struct {
+	name    string
+	op      stackOp
+	started time.Time
+	done    chan loadResult
+	result  *loadResult // Полученный результат, ждущий истечения minLoadingTime
+
+	mu       sync.Mutex
+	progress float64 // От 0 до 1
+	step     string  // Текущий этап для экрана загрузки
+}
+
+func newLoader(name string, op stackOp) *loader {
+	return &loader{
+		name:    name,
+		op:      op,
+		started: time.Now(),
+		done:    make(chan loadResult, 1),
+	}
+}
+
+// report обновляет ход загрузки; вызывается из горутины загрузки
+func (l *loader) report(progress float64, step string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.progress = progress
+	l.step = step
+}
+
+// status возвращает текущий прогресс и этап
+func (l *loader) status() (float64, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.progress, l.step
+}
+
+// poll возвращает результат, когда загрузка завершена и экран показан достаточно долго
+func (l *loader) poll() (loadResult, bool) {
+	if l.result == nil {
+		select {
+		case r := <-l.done:
+			l.result = &r
+		default:
+			return loadResult{}, false
+		}
+	}
+	if time.Since(l.started) < minLoadingTime {
+		return loadResult{}, false
+	}
+	return *l.result, true
+}
@@ -0,0 +1,198 @@
+package gamestate
+
+import (
+	"log/slog"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"main.go/controls"
+	"main.go/resourses"
+	"main.go/scenes"
+)
+
+// Scene экран игры: уровень, меню или окно поверх них.
+// Кроме Update и Draw сцена может реализовать хуки жизненного цикла:
+// OnEnter (попала в стек), OnExit (убрана из стека), OnPause (поверх открыли
+// другую сцену) и OnResume (верхнюю сцену закрыли), а также UpdateBackground,
+// который вызывается каждый тик, пока сцена лежит под другими.
+type Scene interface {
+	Update() error
+	Draw(screen *ebiten.Image)
+}
+
+type (
+	sceneEnterer interface{ OnEnter() }
+	sceneExiter  interface{ OnExit() }
+	scenePauser  interface{ OnPause() }
+	sceneResumer interface{ OnResume() }
+
+	sceneBackground interface{ UpdateBackground() error }
+)
+
+// SceneFactory создает сцену; ресурсы из SceneSpec.Assets к этому моменту загружены.
+// Для сцен с экраном загрузки вызывается не в основной горутине.
+type SceneFactory func(g *Game) (Scene, error)
+
+// SceneSpec описание зарегистрированной сцены
+type SceneSpec struct {
+	New     SceneFactory
+	Assets  []string // Группы ресурсов, которые загружаются до создания сцены
+	Step    string   // Этап на экране загрузки, пока сцена создается
+	Instant bool     // Создается сразу, без экрана загрузки (окна поверх уровня)
+	Overlay bool     // Открывается только поверх другой сцены, игру с нее начать нельзя
+}
+
+var registry = make(map[string]SceneSpec)
+
+// RegisterScene регистрирует сцену под именем, по которому ее открывают уровни
+func RegisterScene(name string, spec SceneSpec) {
+	registry[name] = spec
+}
+
+// StartScenes возвращает по алфавиту имена сцен, с которых можно начать игру
+func StartScenes() []string {
+	var names []string
+	for name, spec := range registry {
+		if !spec.Overlay {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stackOp способ, которым новая сцена попадает в стек
+type stackOp int
+
+const (
+	opPush    stackOp = iota // Поверх текущей, текущая ставится на паузу
+	opReplace                // Вместо верхней сцены
+	opSwitch                 // Вместо всего стека
+)
+
+// sceneEntry сцена в стеке вместе со всем, что ей принадлежит
+type sceneEntry struct {
+	name    string
+	scene   Scene
+	assets  []string          // Захваченные сценой группы ресурсов
+	sources []controls.Source // Источники ввода сцены, обновляются, пока она наверху
+}
+
+// PushScene открывает сцену поверх текущей
+func (g *Game) PushScene(name string) {
+	g.requestScene(name, opPush, g.transitions.Default)
+}
+
+// ReplaceScene заменяет верхнюю сцену
+func (g *Game) ReplaceScene(name string) {
+	g.requestScene(name, opReplace, g.transitions.Default)
+}
+
+// SwitchScene закрывает все сцены и открывает новую с переходом по умолчанию
+func (g *Game) SwitchScene(name string) {
+	g.requestScene(name, opSwitch, g.transitions.Default)
+}
+
+// SwitchSceneWith закрывает все сцены и открывает новую с заданным переходом
+func (g *Game) SwitchSceneWith(name string, transition scenes.Transition) {
+	g.requestScene(name, opSwitch, transition)
+}
+
+// PopScene закрывает верхнюю сцену и возвращает управление предыдущей
+func (g *Game) PopScene() {
+	top := g.top()
+	if top == nil {
+		return
+	}
+	g.transitions.start(g.transitions.Default)
+	g.stack = g.stack[:len(g.stack)-1]
+	g.exitScene(top)
+	if top = g.top(); top != nil {
+		if h, ok := top.scene.(sceneResumer); ok {
+			h.OnResume()
+		}
+	}
+}
+
+// requestScene создает сцену: мгновенные сразу, остальные в фоне с экраном загрузки.
+// Переход проигрывается от текущего кадра к экрану загрузки и от него к новой сцене.
+func (g *Game) requestScene(name string, op stackOp, transition scenes.Transition) {
+	g.pending = name
+	g.pendingOp = op
+	g.transitions.next = transition
+	g.transitions.start(transition)
+
+	spec, ok := registry[name]
+	if !ok || !spec.Instant {
+		g.state = Loading
+		g.loader = nil // Загрузка начнется в следующем Update
+		return
+	}
+
+	g.sources = nil
+	if err := resourses.AcquireBundles(spec.Assets...); err != nil {
+		g.showError(err, name, op)
+		return
+	}
+	scene, err := spec.New(g)
+	if err != nil {
+		resourses.ReleaseBundles(spec.Assets...)
+		g.showError(err, name, op)
+		return
+	}
+	g.install(&sceneEntry{name: name, scene: scene, assets: spec.Assets, sources: g.sources}, op)
+	g.sources = nil
+}
+
+// install помещает созданную сцену в стек и вызывает хуки затронутых сцен
+func (g *Game) install(e *sceneEntry, op stackOp) {
+	switch op {
+	case opPush:
+		if top := g.top(); top != nil {
+			if h, ok := top.scene.(scenePauser); ok {
+				h.OnPause()
+			}
+		}
+	case opReplace:
+		if top := g.top(); top != nil {
+			g.stack = g.stack[:len(g.stack)-1]
+			g.exitScene(top)
+		}
+	case opSwitch:
+		g.exitAll()
+	}
+
+	g.stack = append(g.stack, e)
+	slog.Debug("Сцена открыта", "scene", e.name, "depth", len(g.stack))
+	if h, ok := e.scene.(sceneEnterer); ok {
+		h.OnEnter()
+	}
+	g.state = Playing
+}
+
+// exitScene вызывает OnExit и освобождает ресурсы сцены.
+// Ресурсы новой сцены к этому моменту уже захвачены, поэтому общие не выгружаются.
+func (g *Game) exitScene(e *sceneEntry) {
+	slog.Debug("Сцена закрыта", "scene", e.name)
+	if h, ok := e.scene.(sceneExiter); ok {
+		h.OnExit()
+	}
+	resourses.ReleaseBundles(e.assets...)
+}
+
+// exitAll закрывает все сцены сверху вниз
+func (g *Game) exitAll() {
+	for len(g.stack) > 0 {
+		top := g.top()
+		g.stack = g.stack[:len(g.stack)-1]
+		g.exitScene(top)
+	}
+}
+
+// top возвращает верхнюю сцену стека или nil
+func (g *Game) top() *sceneEntry {
+	if len(g.stack) == 0 {
+		return nil
+	}
+	return g.stack[len(g.stack)-1]
+}
@@ -0,0 +1,80 @@
+package gamestate
+
+import (
+	"main.go/controls"
+	"main.go/levels/level1"
+	"main.go/levels/level2"
+	"main.go/levels/level5"
+	"main.go/levels/menu"
+	"main.go/levels/pause"
+	"main.go/levels/settings"
+	sprites "main.go/resourses/img"
+	"main.go/resourses/maps"
+	"main.go/scenes"
+)
+
+func init() {
+	RegisterScene(scenes.Menu, SceneSpec{
+		Assets: []string{sprites.Bundle},
+		New: func(g *Game) (Scene, error) {
+			return menu.New(g), nil
+		},
+	})
+	RegisterScene(scenes.Level1, SceneSpec{
+		Assets: []string{sprites.Bundle, maps.Bundle},
+		Step:   "Connecting to server",
+		New:    newLevel1,
+	})
+	RegisterScene(scenes.Pause, SceneSpec{
+		Instant: true,
+		Overlay: true,
+		New: func(g *Game) (Scene, error) {
+			return pause.New(g), nil
+		},
+	})
+	RegisterScene(scenes.Controls, SceneSpec{
+		Instant: true,
+		Overlay: true,
+		New: func(g *Game) (Scene, error) {
+			return pause.NewControls(g), nil
+		},
+	})
+	RegisterScene(scenes.Settings, SceneSpec{
+		Instant: true,
+		Overlay: true,
+		New: func(g *Game) (Scene, error) {
+			return settings.New(g), nil
+		},
+	})
+	RegisterScene(scenes.Level2, SceneSpec{
+		New: func(g *Game) (Scene, error) {
+			return level2.New(g), nil
+		},
+	})
+	RegisterScene(scenes.Level5, SceneSpec{
+		New: func(g *Game) (Scene, error) {
+			return level5.New(g), nil
+		},
+	})
+}
+
+// newLevel1 подключает к серверу одного игрока или двоих на разделенном экране
+func newLevel1(g *Game) (Scene, error) {
+	var (
+		level *level1.Level1
+		err   error
+	)
+	if g.secondName != "" {
+		// Первый игрок на клавиатуре, второй на геймпаде
+		level, err = level1.NewSplitScreen(g, []level1.LocalPlayer{
+			{Name: g.playerName, Skin: g.playerSkin, Keymap: controls.KeyboardKeymap},
+			{Name: g.secondName, Skin: g.secondSkin, Keymap: controls.GamepadKeymap},
+		})
+	} else {
+		level, err = level1.New(g, g.playerName, g.playerSkin)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return level, nil
+}
@@ -0,0 +1,160 @@
+package gamestate
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"main.go/scenes"
+)
+
+const (
+	defaultTransitionDuration = 400 * time.Millisecond
+	maxPixelateBlock          = 32 // Размер «пикселя» в середине перехода Pixelate
+)
+
+// transitioner плавно сменяет изображение при переключении сцен.
+// Все сцены рисуются во внеэкранное изображение; при смене сцены последний
+// показанный кадр запоминается, и в течение Duration он смешивается
+// с живым изображением новой сцены.
+type transitioner struct {
+	Default  scenes.Transition // Переход для смены сцены без явного указания
+	Duration time.Duration
+
+	next    scenes.Transition // Переход для текущей смены сцены (до загрузки и после нее)
+	kind    scenes.Transition // Проигрываемый сейчас переход
+	active  bool
+	elapsed time.Duration
+
+	content  *ebiten.Image // Живое изображение текущих сцен
+	composed *ebiten.Image // Результат смешивания
+	from     *ebiten.Image // Кадр, показанный перед сменой
+	small    *ebiten.Image // Уменьшенная копия для Pixelate
+	shown    *ebiten.Image // Что было показано в последнем кадре (content или composed)
+}
+
+func newTransitioner() transitioner {
+	return transitioner{Default: scenes.Fade, Duration: defaultTransitionDuration, next: scenes.Fade}
+}
+
+// start запускает переход от последнего показанного кадра
+func (t *transitioner) start(kind scenes.Transition) {
+	if kind == scenes.Cut || t.Duration <= 0 || t.shown == nil {
+		t.active = false
+		return
+	}
+	t.from.Clear()
+	t.from.DrawImage(t.shown, nil)
+	t.kind = kind
+	t.elapsed = 0
+	t.active = true
+}
+
+// update продвигает переход на длительность тика
+func (t *transitioner) update(dt time.Duration) {
+	if !t.active {
+		return
+	}
+	t.elapsed += dt
+	if t.elapsed >= t.Duration {
+		t.active = false
+	}
+}
+
+// draw рисует содержимое через drawContent, при активном переходе смешивая его с прежним кадром
+func (t *transitioner) draw(screen *ebiten.Image, drawContent func(*ebiten.Image)) {
+	t.ensureBuffers(screen.Bounds().Size())
+
+	t.content.Clear()
+	drawContent(t.content)
+	t.shown = t.content
+	if t.active {
+		progress := math.Min(float64(t.elapsed)/float64(t.Duration), 1)
+		t.composed.Clear()
+		t.compose(t.composed, progress)
+		t.shown = t.composed
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(screen.Bounds().Min.X), float64(screen.Bounds().Min.Y))
+	screen.DrawImage(t.shown, op)
+}
+
+// compose смешивает прежний кадр from и новое содержимое content; progress от 0 до 1
+func (t *transitioner) compose(dst *ebiten.Image, progress float64) {
+	w, h := dst.Bounds().Dx(), dst.Bounds().Dy()
+	switch t.kind {
+	case scenes.Fade:
+		// Первая половина - затемнение старой сцены, вторая - появление новой
+		src, dark := t.from, progress*2
+		if progress >= 0.5 {
+			src, dark = t.content, (1-progress)*2
+		}
+		op := &ebiten.DrawImageOptions{}
+		op.ColorScale.Scale(float32(1-dark), float32(1-dark), float32(1-dark), 1)
+		dst.Fill(color.Black)
+		dst.DrawImage(src, op)
+	case scenes.Crossfade:
+		dst.DrawImage(t.from, nil)
+		op := &ebiten.DrawImageOptions{}
+		op.ColorScale.ScaleAlpha(float32(progress))
+		dst.DrawImage(t.content, op)
+	case scenes.Wipe:
+		dst.DrawImage(t.from, nil)
+		edge := int(float64(w) * progress)
+		dst.DrawImage(t.content.SubImage(image.Rect(0, 0, edge, h)).(*ebiten.Image), nil)
+	case scenes.Pixelate:
+		src, amount := t.from, progress*2
+		if progress >= 0.5 {
+			src, amount = t.content, (1-progress)*2
+		}
+		t.pixelate(dst, src, 1+int(amount*(maxPixelateBlock-1)))
+	default:
+		dst.DrawImage(t.content, nil)
+	}
+}
+
+// pixelate рисует src крупными блоками block x block
+func (t *transitioner) pixelate(dst, src *ebiten.Image, block int) {
+	if block <= 1 {
+		dst.DrawImage(src, nil)
+		return
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	sw, sh := (w+block-1)/block, (h+block-1)/block
+
+	t.small.Clear()
+	down := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	down.GeoM.Scale(1/float64(block), 1/float64(block))
+	t.small.DrawImage(src, down)
+
+	up := &ebiten.DrawImageOptions{Filter: ebiten.FilterNearest}
+	up.GeoM.Scale(float64(block), float64(block))
+	dst.DrawImage(t.small.SubImage(image.Rect(0, 0, sw, sh)).(*ebiten.Image), up)
+}
+
+// ensureBuffers пересоздает внеэкранные изображения при смене размера экрана
+func (t *transitioner) ensureBuffers(size image.Point) {
+	if t.content != nil && t.content.Bounds().Size() == size {
+		return
+	}
+	for _, img := range []*ebiten.Image{t.content, t.composed, t.small} {
+		if img != nil {
+			img.Deallocate()
+		}
+	}
+	t.content = ebiten.NewImage(size.X, size.Y)
+	t.composed = ebiten.NewImage(size.X, size.Y)
+	t.small = ebiten.NewImage(size.X, size.Y)
+
+	// Прежний кадр переносится в новый буфер, чтобы идущий переход не оборвался
+	from := ebiten.NewImage(size.X, size.Y)
+	if t.from != nil {
+		from.DrawImage(t.from, nil)
+		t.from.Deallocate()
+	}
+	t.from = from
+	t.shown = nil
+}
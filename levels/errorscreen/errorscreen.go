@@ -0,0 +1,77 @@
+package errorscreen
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	input "github.com/quasilyte/ebitengine-input"
+	"main.go/controls"
+	"main.go/scenes"
+	"main.go/ui"
+)
+
+type GameInterface interface {
+	RetryScene()             // Повторить открытие сцены, завершившейся ошибкой
+	SwitchScene(name string) // Имя из пакета scenes
+	UIScale() float64
+	NewInputSource(playerID uint8, keymap input.Keymap) controls.Source
+}
+
+// ErrorScreen сообщение об ошибке поверх сцены, в которой она случилась.
+// Позволяет повторить попытку или вернуться в меню; если не открылось
+// само меню, вместо возврата предлагается выход.
+type ErrorScreen struct {
+	game    GameInterface
+	input   controls.Source
+	err     error
+	canMenu bool // Есть куда вернуться
+	options *ui.ButtonList
+}
+
+// New создает экран ошибки; canMenu - можно ли вернуться в меню
+func New(game GameInterface, err error, canMenu bool) *ErrorScreen {
+	last := "Quit"
+	if canMenu {
+		last = "Return to Menu"
+	}
+	return &ErrorScreen{
+		game:    game,
+		input:   game.NewInputSource(0, controls.MenuKeymap),
+		err:     err,
+		canMenu: canMenu,
+		options: ui.NewButtonList("Retry", last),
+	}
+}
+
+func (e *ErrorScreen) Update() error {
+	switch e.options.Update(e.input) {
+	case 0:
+		e.game.RetryScene()
+	case 1:
+		if !e.canMenu {
+			return ebiten.Termination
+		}
+		e.game.SwitchScene(scenes.Menu)
+	}
+	return nil
+}
+
+func (e *ErrorScreen) Draw(screen *ebiten.Image) {
+	bounds := screen.Bounds()
+	// Затемняем сцену под сообщением
+	vector.DrawFilledRect(screen, float32(bounds.Min.X), float32(bounds.Min.Y), float32(bounds.Dx()), float32(bounds.Dy()), color.RGBA{0, 0, 0, 200}, false)
+
+	scale := e.game.UIScale()
+	width, height := int(240*scale), int(40*scale)
+	x := bounds.Min.X + (bounds.Dx()-width)/2
+	y := bounds.Min.Y + bounds.Dy()/2
+	ebitenutil.DebugPrintAt(screen, "Error: "+e.err.Error(), x, y-int(60*scale))
+	e.options.Layout(x, y, width, height, int(10*scale))
+	e.options.Draw(screen)
+}
+
+func (e *ErrorScreen) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return outsideWidth, outsideHeight
+}
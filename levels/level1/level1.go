@@ -1,11 +1,9 @@
 package level1
 
 import (
-	"encoding/json"
 	"fmt"
+	"image"
 	"image/color"
-	"log"
-	"net"
 	"sort"
 	"strconv"
 	"time"
@@ -13,21 +11,33 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
+	input "github.com/quasilyte/ebitengine-input"
+	"main.go/camera"
+	"main.go/controls"
 	sprites "main.go/resourses/img"
+	"main.go/resourses/maps"
+	"main.go/scenes"
+	"main.go/ui"
+)
+
+const (
+	zoomStep       = 1.25 // Во сколько раз меняется приближение за нажатие клавиши или щелчок колеса
+	worldScale     = 1.0  // Пикселей виртуального экрана на единицу мира без приближения
+	joystickRadius = 80   // Радиус экранного джойстика при UI scale 1
 )
 
 type Player struct {
-	ID             int                     `json:"id"`
-	X              float64                 `json:"x"`
-	Y              float64                 `json:"y"`
-	PrevX          float64                 // Предыдущая X позиция для интерполяции
-	PrevY          float64                 // Предыдущая Y позиция для интерполяции
-	LastUpdateTime time.Time               // Время последнего обновления с сервера
-	Name           string                  `json:"name"` // Добавляем JSON-тег для имени
-	Skin           string                  `json:"skin"` // Добавляем JSON-тег для скина
-	FlipX          bool                    `json:"flipX"`
-	Sprite         *sprites.AnimatedSprite // Спрайт игрока
-	Points         int                     `json:"points"` // Добавляем поле для очков
+	ID             int                    `json:"id"`
+	X              float64                `json:"x"`
+	Y              float64                `json:"y"`
+	PrevX          float64                // Предыдущая X позиция для интерполяции
+	PrevY          float64                // Предыдущая Y позиция для интерполяции
+	LastUpdateTime time.Time              // Время последнего обновления с сервера
+	Name           string                 `json:"name"` // Добавляем JSON-тег для имени
+	Skin           string                 `json:"skin"` // Добавляем JSON-тег для скина
+	FlipX          bool                   `json:"flipX"`
+	Points         int                    `json:"points"`         // Добавляем поле для очков
+	Anim           sprites.AnimationState `json:"anim,omitempty"` // Текущая анимация, сервер пересылает ее другим игрокам
 
 }
 
@@ -48,231 +58,159 @@ type GameState struct {
 }
 
 type GameInterface interface {
-	SwitchLevel(level int)
-	GetScale() float64
+	SwitchScene(name string) // Имя из пакета scenes
+	PushScene(name string)   // Открыть окно поверх уровня
+	UIScale() float64        // Масштаб элементов интерфейса, например экранного джойстика
 	SetPlayerInfo(name, skin string)
+	NewInputSource(playerID uint8, keymap input.Keymap) controls.Source
+	VirtualJoystickEnabled() bool
+	ServerAddress() string // Адрес UDP сервера игры
 }
 
 type Level1 struct {
-	game          GameInterface
-	playerID      int
-	playerX       float64
-	playerY       float64
-	capturePoints []CapturePoint
-	players       []Player
-	FlipX         bool
-	Points        int
-	playerName    string
-	playerSkin    string
-	conn          *net.UDPConn
-	done          chan struct{}
-	lastUpdate    time.Time
-	serverAddr    *net.UDPAddr
+	game      GameInterface
+	arena     *maps.Map           // Карта арены из группы ресурсов maps.Bundle
+	sessions  []*session          // По одному подключению на каждого локального игрока
+	viewports []*viewport         // Область экрана для каждого игрока
+	joystick  *ui.VirtualJoystick // Экранный джойстик первого игрока (nil, если выключен)
+	rings     *ringCache          // Текстуры колец точек захвата
+	elapsed   time.Duration       // Время на уровне по тикам, для пульсации спорных точек
 }
 
-// New инициализирует уровень и подключается к серверу через UDP
-func New(game GameInterface, playerName, playerSkin string) *Level1 {
-	// Настраиваем UDP соединение
-	serverAddr, err := net.ResolveUDPAddr("udp", "localhost:8080")
-	if err != nil {
-		log.Fatal("Ошибка при резолве адреса UDP:", err)
-	}
-	localAddr, err := net.ResolveUDPAddr("udp", "localhost:8089") // Уникальный порт для первого клиента
-	if err != nil {
-		log.Fatal("Ошибка при резолве адреса UDP:", err)
-	}
-	conn, err := net.DialUDP("udp", localAddr, serverAddr)
-	if err != nil {
-		log.Fatal("Ошибка подключения к UDP серверу:", err)
-	}
-
-	level := &Level1{
-		game:       game,
-		conn:       conn,
-		serverAddr: serverAddr,
-		done:       make(chan struct{}),
-		playerID:   0, // Пока ID неизвестен
-		playerName: playerName,
-		playerSkin: playerSkin,
-		Points:     0,
-	}
-
-	// Получение playerID от сервера
-	level.requestPlayerID()
-
-	go level.listenForUpdates()
-
-	return level
+// viewport область экрана, в которой мир показывается глазами одного игрока
+type viewport struct {
+	rect   image.Rectangle
+	camera *camera.Camera // Следует за своим игроком в пределах арены
 }
 
-func (l *Level1) requestPlayerID() {
-	// Отправляем запрос на получение playerID
-	initialMsg := map[string]interface{}{
-		"request": "get_player_id",
-		"name":    l.playerName, // Передаем имя игрока
-		"skin":    l.playerSkin,
-	}
-	data, _ := json.Marshal(initialMsg)
-	l.conn.Write(data)
-
-	// Ожидаем ответ от сервера с playerID
-	buffer := make([]byte, 2048)
-	n, _, err := l.conn.ReadFrom(buffer)
-	if err != nil {
-		log.Println("Ошибка получения данных от сервера:", err)
-		return
-	}
+// newViewport создает обзор с камерой на игроке сессии в пределах арены
+func newViewport(s *session, arena *maps.Map) *viewport {
+	cam := camera.New(s.playerX, s.playerY)
+	cam.Bounds = camera.Rect{MaxX: arena.Arena.Width, MaxY: arena.Arena.Height}
+	return &viewport{camera: cam}
+}
 
-	var response map[string]interface{}
-	if err := json.Unmarshal(buffer[:n], &response); err != nil {
-		log.Println("Ошибка разбора данных от сервера:", err)
-		return
-	}
+// update ведет камеру за игроком и трясет ее от ударов за этот тик
+func (v *viewport) update(s *session, dt time.Duration) {
+	v.camera.Shake(s.takeImpact())
+	v.camera.Follow(s.playerX, s.playerY, dt.Seconds())
+	v.camera.Update(dt.Seconds())
+}
 
-	// Сохраняем playerID, полученный от сервера
-	if id, ok := response["id"].(float64); ok {
-		l.playerID = int(id)
-		log.Printf("Получен playerID: %d", l.playerID)
-	}
+// New инициализирует уровень и подключается к серверу через UDP
+func New(game GameInterface, playerName, playerSkin string) (*Level1, error) {
+	return NewSplitScreen(game, []LocalPlayer{
+		{Name: playerName, Skin: playerSkin, Keymap: controls.DefaultKeymap},
+	})
 }
 
-// listenForUpdates получает обновления от сервера
-func (l *Level1) listenForUpdates() {
-	buffer := make([]byte, 2048)
-	for {
-		n, _, err := l.conn.ReadFromUDP(buffer)
-		if err != nil {
-			log.Println("Ошибка при чтении данных от сервера:", err)
-			return
+// NewSplitScreen создает уровень для нескольких игроков за одним компьютером.
+// Каждый получает свое подключение к серверу, раскладку и половину экрана.
+// Если сервер недоступен, уже открытые подключения закрываются и возвращается ошибка.
+func NewSplitScreen(game GameInterface, players []LocalPlayer) (*Level1, error) {
+	if maps.Arena == nil {
+		return nil, fmt.Errorf("карта арены не загружена")
+	}
+	level := &Level1{game: game, arena: maps.Arena, rings: newRingCache()}
+	for i, player := range players {
+		// Номер игрока выбирает его устройства ввода, например свой геймпад
+		src := game.NewInputSource(uint8(i), player.Keymap)
+		if i == 0 && game.VirtualJoystickEnabled() {
+			level.joystick = ui.NewVirtualJoystick(src, joystickRadius*game.UIScale())
+			src = level.joystick
 		}
-
-		var gameState GameState
-		err = json.Unmarshal(buffer[:n], &gameState)
+		s, err := newSession(game.ServerAddress(), basePort+i, player, src, &level.arena.Arena)
 		if err != nil {
-			log.Println("Ошибка при десериализации данных:", err)
-			continue
+			for _, opened := range level.sessions {
+				opened.close()
+			}
+			return nil, fmt.Errorf("подключение к серверу: %w", err)
 		}
-
-		// Обновляем состояние игры на основе полученных данных
-		l.updateGameState(gameState)
+		level.sessions = append(level.sessions, s)
+		level.viewports = append(level.viewports, newViewport(s, level.arena))
 	}
+	return level, nil
 }
 
-func (l *Level1) updateGameState(state GameState) {
-	l.players = state.Players
-	l.capturePoints = state.CapturePoints
-
-	// Обновляем координаты только для своего игрока
-	for i, player := range state.Players {
-		if player.ID == l.playerID {
-			l.Points = player.Points
-			// Сохраняем предыдущую позицию
-			continue
-		} else {
-			// Для других игроков, просто обновляем их предыдущие координаты
-			l.players[i].PrevX = l.players[i].X
-			l.players[i].PrevY = l.players[i].Y
-			l.players[i].LastUpdateTime = time.Now()
-			l.players[i].Name = player.Name // Обновляем имя
-			l.players[i].Skin = player.Skin // Обновляем скин
-			l.players[i].FlipX = player.FlipX
-
-		}
-
+// OnExit закрывает подключения к серверу и освобождает текстуры, когда уровень покидают
+func (l *Level1) OnExit() {
+	for _, s := range l.sessions {
+		s.close()
 	}
+	l.rings.dispose()
 }
 
 func (l *Level1) Update() error {
-
-	keys := map[string]bool{
-		"w": ebiten.IsKeyPressed(ebiten.KeyW),
-		"s": ebiten.IsKeyPressed(ebiten.KeyS),
-		"a": ebiten.IsKeyPressed(ebiten.KeyA),
-		"d": ebiten.IsKeyPressed(ebiten.KeyD),
-	}
-
-	speed := 10.0
-	originalX, originalY := l.playerX, l.playerY
-
-	if keys["w"] {
-		l.playerY -= speed
-	}
-	if keys["s"] {
-		l.playerY += speed
-	}
-	if keys["a"] {
-		l.playerX -= speed
-		l.FlipX = true
-	}
-	if keys["d"] {
-		l.playerX += speed
-	}
-
-	// Если позиция изменилась, отправляем данные на сервер
-	if originalX != l.playerX || originalY != l.playerY {
-		l.sendPositionUpdate()
-	}
-
-	if ebiten.IsKeyPressed(ebiten.KeyP) {
-		l.sendAction("pull")
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyO) {
-		l.sendAction("push")
-	}
-
-	return nil
-}
-
-func (l *Level1) sendPositionUpdate() {
-	if time.Since(l.lastUpdate) > 10*time.Millisecond {
-		// Формируем данные для отправки
-		data := map[string]interface{}{
-			"id":    l.playerID,
-			"x":     l.playerX,
-			"y":     l.playerY,
-			"flipX": l.FlipX, // Добавляем состояние FlipX
+	if l.joystick != nil {
+		// UI scale могли поменять в настройках, пока уровень был под паузой
+		l.joystick.Radius = joystickRadius * l.game.UIScale()
+		l.joystick.Update()
+	}
+	// Анимации идут по фиксированному шагу тика, а не по числу отрисовок
+	dt := time.Second / time.Duration(ebiten.TPS())
+	l.elapsed += dt
+	for _, s := range l.sessions {
+		if err := s.checkConnection(); err != nil {
+			return err
 		}
-
-		// Сериализуем данные в JSON
-		jsonData, err := json.Marshal(data)
-		if err != nil {
-			log.Println("Ошибка сериализации данных:", err)
-			return
+		// Пауза открывается любым из локальных игроков
+		if s.input.ActionIsJustPressed(controls.ActionPause) {
+			l.game.PushScene(scenes.Pause)
+			return nil
 		}
+	}
+	for i, s := range l.sessions {
+		l.pointerWalk(s, l.viewports[i])
+		s.update(dt)
 
-		// Отправляем сериализованные данные через UDP
-		_, err = l.conn.Write(jsonData)
-		if err != nil {
-			log.Println("Ошибка отправки данных через UDP:", err)
-			return
+		cam := l.viewports[i].camera
+		if s.input.ActionIsJustPressed(controls.ActionZoomIn) {
+			cam.ZoomBy(zoomStep)
 		}
-
-		// Обновляем время последней отправки
-		l.lastUpdate = time.Now()
+		if s.input.ActionIsJustPressed(controls.ActionZoomOut) {
+			cam.ZoomBy(1 / zoomStep)
+		}
+		l.viewports[i].update(s, dt)
 	}
+	return nil
 }
 
-func (l *Level1) sendAction(action string) {
-	// Формируем данные для отправки
-	data := map[string]interface{}{
-		"id":     l.playerID,
-		"action": action,
+// pointerWalk ведет игрока к точке мира под зажатой кнопкой мыши или пальцем,
+// если указатель в его области экрана. Первого игрока с экранным джойстиком
+// ведет только джойстик.
+func (l *Level1) pointerWalk(s *session, v *viewport) {
+	if l.joystick != nil && s.input == controls.Source(l.joystick) {
+		return
 	}
-
-	// Сериализуем данные в JSON
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		log.Println("Ошибка сериализации данных:", err)
+	if !s.input.ActionIsPressed(controls.ActionPointer) {
 		return
 	}
-
-	// Отправляем данные через UDP
-	_, err = l.conn.Write(jsonData)
-	if err != nil {
-		log.Println("Ошибка отправки данных через UDP:", err)
+	x, y := s.input.PointerPos()
+	if !image.Pt(int(x), int(y)).In(v.rect) {
 		return
 	}
+	s.walkTo(v.camera.ScreenToWorld(x, y))
+}
+
+// UpdateBackground вызывается, пока уровень под паузой: ввод не обрабатывается,
+// но другие игроки продолжают двигаться, а сервер получает признаки жизни
+func (l *Level1) UpdateBackground() error {
+	// Касание, удерживаемое при открытии паузы, не должно вести игрока после нее
+	if l.joystick != nil {
+		l.joystick.Reset()
+	}
+	dt := time.Second / time.Duration(ebiten.TPS())
+	l.elapsed += dt
+	for i, s := range l.sessions {
+		if err := s.checkConnection(); err != nil {
+			return err
+		}
+		s.idle(dt)
+		l.viewports[i].update(s, dt)
+	}
+	return nil
 }
+
 func easeInOut(t float64) float64 {
 	if t < 0.5 {
 		return 2 * t * t
@@ -286,33 +224,43 @@ func lerp(start, end, t float64) float64 {
 }
 
 func (l *Level1) Draw(screen *ebiten.Image) {
-	scale := l.game.GetScale() // Получаем масштаб
-
-	// Определяем флаг для отражения спрайта игрока
-
-	// Определяем направление движения игрока (налево)
-	if ebiten.IsKeyPressed(ebiten.KeyA) {
-		l.FlipX = true
-	} else {
-		l.FlipX = false
+	// Делим экран по ширине между локальными игроками
+	bounds := screen.Bounds()
+	width := bounds.Dx() / len(l.viewports)
+	for i, v := range l.viewports {
+		v.rect = image.Rect(bounds.Min.X+i*width, bounds.Min.Y, bounds.Min.X+(i+1)*width, bounds.Max.Y)
+		l.drawView(screen.SubImage(v.rect).(*ebiten.Image), l.sessions[i], v)
+		if i > 0 {
+			// Разделительная линия между областями
+			vector.StrokeLine(screen, float32(v.rect.Min.X), float32(v.rect.Min.Y), float32(v.rect.Min.X), float32(v.rect.Max.Y), 2, color.White, false)
+		}
 	}
+}
+
+// drawView рисует мир глазами одного локального игрока
+func (l *Level1) drawView(screen *ebiten.Image, s *session, v *viewport) {
+	// Камера показывает мир в своей области экрана
+	v.camera.SetViewport(float64(v.rect.Min.X), float64(v.rect.Min.Y), float64(v.rect.Dx()), float64(v.rect.Dy()), worldScale)
+	scale := v.camera.Scale()
 
 	// Подготавливаем параметры для отрисовки спрайта игрока
 	playerOp := &ebiten.DrawImageOptions{}
-	if l.FlipX {
+	if s.FlipX {
 		playerOp.GeoM.Scale(-1, 1) // Отражаем по оси X
 	}
 
-	// Масштабируем координаты игрока только для отрисовки
-	scaledPlayerX := l.playerX * scale
-	scaledPlayerY := l.playerY * scale
+	// Слои карты под игроками
+	l.arena.DrawBelow(screen, v.camera)
+
+	// Переводим координаты игрока в экранные только для отрисовки
+	scaledPlayerX, scaledPlayerY := v.camera.WorldToScreen(s.playerX, s.playerY)
 
 	// Отрисовываем спрайт игрока с правильной позицией
-	sprites.Sprites[l.playerSkin].Draw(screen, scaledPlayerX, scaledPlayerY, scale, l.FlipX, playerOp)
+	s.animator.Draw(screen, scaledPlayerX, scaledPlayerY, scale, s.FlipX, playerOp)
 
 	// Отрисовка врагов
-	for _, p := range l.players {
-		if p.ID == l.playerID {
+	for _, p := range s.players {
+		if p.ID == s.playerID {
 			continue
 		}
 
@@ -321,14 +269,8 @@ func (l *Level1) Draw(screen *ebiten.Image) {
 			t = 1
 		}
 
-		// Масштабируем координаты только для отрисовки
-		scaledPrevX := p.PrevX * scale
-		scaledX := p.X * scale
-		scaledPrevY := p.PrevY * scale
-		scaledY := p.Y * scale
-
-		x := lerp(scaledPrevX, scaledX, t)
-		y := lerp(scaledPrevY, scaledY, t)
+		// Интерполируем в мировых координатах, на экран переводим только для отрисовки
+		x, y := v.camera.WorldToScreen(lerp(p.PrevX, p.X, t), lerp(p.PrevY, p.Y, t))
 
 		// Подготавливаем параметры для отрисовки спрайта врага
 		enemyOp := &ebiten.DrawImageOptions{}
@@ -336,32 +278,43 @@ func (l *Level1) Draw(screen *ebiten.Image) {
 			enemyOp.GeoM.Scale(-1, 1) // Отражаем по оси X
 		}
 		// Устанавливаем позицию врага
-		sprites.Sprites[p.Skin].Draw(screen, x, y, scale, p.FlipX, enemyOp) // Отрисовка врага
+		s.remoteAnimator(p).Draw(screen, x, y, scale, p.FlipX, enemyOp) // Отрисовка врага
 		pointsText := fmt.Sprintf(p.Name)
 		ebitenutil.DebugPrintAt(screen, pointsText, int(x), int(y)-20)
 	}
-	playerPointsText := fmt.Sprintf(l.playerName)
+	playerPointsText := fmt.Sprintf(s.playerName)
 	ebitenutil.DebugPrintAt(screen, playerPointsText, int(scaledPlayerX), int(scaledPlayerY)-20)
-	for _, cp := range l.capturePoints {
-		// Отображение информации о точке захвата
-		cpX := cp.X * scale // Масштабируем координаты захватной точки
-		cpY := cp.Y * scale
 
+	// Слои карты поверх игроков
+	l.arena.DrawAbove(screen, v.camera)
+
+	capturePoints := s.capturePoints
+	if len(capturePoints) == 0 {
+		// Пока сервер не прислал состояние, показываем размещение точек с карты
+		capturePoints = l.mapCapturePoints()
+	}
+	for _, cp := range capturePoints {
+		// Отображение информации о точке захвата
+		cpX, cpY := v.camera.WorldToScreen(cp.X, cp.Y) // Экранные координаты захватной точки
 		ebitenutil.DebugPrintAt(screen, "CP: X="+strconv.FormatFloat(cp.X, 'f', 1, 64)+" Y="+strconv.FormatFloat(cp.Y, 'f', 1, 64), int(cpX), int(cpY)-int(20*scale))
 
-		// Масштабируем радиус захватной точки
+		// Слишком маленькую точку увеличиваем, чтобы кольцо было видно
 		if cp.Radius < 10 {
 			cp.Radius = 10
 		}
-		radius := cp.Radius * scale
+		// Спорная точка, которую захватывает не владелец, пульсирует
+		ringScale, ringAlpha := 1.0, float32(1)
+		if cp.CurrentCapturingPlayer != 0 && cp.CurrentCapturingPlayer != cp.CapturingPlayer {
+			ringScale, ringAlpha = pulse(l.elapsed.Seconds())
+		}
 
 		// Основной круг точки захвата (красный, если не захвачена)
 		if !cp.IsCaptured {
-			drawCircleOutlineWithEffects(screen, cpX, cpY, radius, color.RGBA{255, 0, 0, 100})
+			l.rings.draw(screen, cpX, cpY, cp.Radius, scale, color.RGBA{255, 0, 0, 100}, ringScale, ringAlpha)
 		} else {
 			// Отображаем цвет игрока, который владеет точкой
 			playerColor := getPlayerColor(cp.CapturingPlayer)
-			drawCircleOutlineWithEffects(screen, cpX, cpY, radius, playerColor)
+			l.rings.draw(screen, cpX, cpY, cp.Radius, scale, playerColor, ringScale, ringAlpha)
 		}
 
 		// Проверяем, захватывается ли точка
@@ -379,37 +332,40 @@ func (l *Level1) Draw(screen *ebiten.Image) {
 			// Получаем цвет игрока, который сейчас захватывает
 			capturingPlayerColor := getPlayerColor(cp.CurrentCapturingPlayer)
 
-			// Рисуем растущий круг цвета игрока, который захватывает точку
-			animatedRadius := radius * progress
-			drawCircleOutlineWithEffects(screen, cpX, cpY, animatedRadius, capturingPlayerColor)
+			// Рисуем растущий круг цвета игрока, который захватывает точку:
+			// та же текстура, что у полного круга, уменьшенная по прогрессу
+			l.rings.draw(screen, cpX, cpY, cp.Radius, scale, capturingPlayerColor, progress, 1)
 
 			// Информация о прогрессе
 			progressText := fmt.Sprintf("Progress: %.0f%%", progress*100)
 			ebitenutil.DebugPrintAt(screen, progressText, int(cpX), int(cpY)-int(40*scale))
-
-			// Если захват завершён
-			if progress == 1 {
-				cp.IsCaptured = true
-				cp.CapturingPlayer = cp.CurrentCapturingPlayer
-				cp.EnterTime = time.Time{}
-			}
-		}
-
-		// Если игрок пытается захватить точку
-		if cp.CurrentCapturingPlayer == cp.CapturingPlayer {
-			continue
 		}
 	}
 
 	// Отображаем текст с учётом масштаба
-	l.drawPlayerScores(screen)
+	l.drawPlayerScores(screen, s, v)
+
+	// Экранный джойстик рисуется поверх мира в области своего игрока
+	if l.joystick != nil && s.input == controls.Source(l.joystick) {
+		l.joystick.SetArea(v.rect)
+		l.joystick.Draw(screen)
+	}
+}
+
+// mapCapturePoints возвращает незахваченные точки в местах, отмеченных на карте
+func (l *Level1) mapCapturePoints() []CapturePoint {
+	points := make([]CapturePoint, 0, len(l.arena.Arena.CapturePoints))
+	for _, cp := range l.arena.Arena.CapturePoints {
+		points = append(points, CapturePoint{X: cp.X, Y: cp.Y, Radius: cp.Radius})
+	}
+	return points
 }
 
 // drawPlayerScores рисует имена и очки всех игроков
-func (l *Level1) drawPlayerScores(screen *ebiten.Image) {
+func (l *Level1) drawPlayerScores(screen *ebiten.Image, s *session, v *viewport) {
 	// Копируем слайс игроков для сортировки (если это глобальная переменная)
-	sortedPlayers := make([]Player, len(l.players))
-	copy(sortedPlayers, l.players)
+	sortedPlayers := make([]Player, len(s.players))
+	copy(sortedPlayers, s.players)
 
 	// Сортируем игроков по количеству очков (от большего к меньшему)
 	sort.Slice(sortedPlayers, func(i, j int) bool {
@@ -417,73 +373,23 @@ func (l *Level1) drawPlayerScores(screen *ebiten.Image) {
 	})
 
 	// Смещение по Y для отрисовки
-	yOffset := 10
+	yOffset := v.rect.Min.Y + 10
 	for _, player := range sortedPlayers {
 		// Форматируем строку: "Имя игрока: Очки"
 		text := fmt.Sprintf("%s: %d", player.Name, player.Points)
 
 		// Отрисовываем текст на экране
-		ebitenutil.DebugPrintAt(screen, text, 10, yOffset)
+		ebitenutil.DebugPrintAt(screen, text, v.rect.Min.X+10, yOffset)
 
 		// Смещаем строку для следующего игрока
 		yOffset += 20
 	}
 }
 
-// Функция для рисования контура круга с градиентом и свечением
-func drawCircleOutlineWithEffects(screen *ebiten.Image, x, y, radius float64, clr color.Color) {
-	if radius <= 0 {
-		return // Не рисуем круг с некорректным радиусом
-	}
-
-	imgWidth := int(2 * radius)
-	imgHeight := int(2 * radius)
-
-	if imgWidth <= 0 || imgHeight <= 0 {
-		return // Проверка на корректные размеры изображения
-	}
-
-	img := ebiten.NewImage(imgWidth, imgHeight)
-	img.Fill(color.Transparent)
-
-	// Добавляем эффект свечения через прозрачность и градиент
-	for r := radius; r > radius-10; r-- { // Градиент по краю круга
-		alpha := uint8(255 * (r / radius)) // Прозрачность градиента по радиусу
-		gradientColor := color.RGBA{uint8(clr.(color.RGBA).R), uint8(clr.(color.RGBA).G), uint8(clr.(color.RGBA).B), alpha}
-		vector.StrokeCircle(img, float32(radius), float32(radius), float32(r), 2, gradientColor, true) // Рисуем градиентный контур
-	}
-
-	// Рисуем основной контур круга
-	vector.StrokeCircle(img, float32(radius), float32(radius), float32(radius), 1, clr, true) // 3 - толщина линии
-
-	// Эффект свечения
-	glowRadius := radius + 2 // Радиус свечения немного больше самого круга
-	for r := radius; r < glowRadius; r++ {
-		alpha := uint8(100 * ((glowRadius - r) / glowRadius)) // Прозрачность по краям свечения
-		glowColor := color.RGBA{uint8(clr.(color.RGBA).R), uint8(clr.(color.RGBA).G), uint8(clr.(color.RGBA).B), alpha}
-		vector.StrokeCircle(img, float32(radius), float32(radius), float32(r), 1, glowColor, true)
-	}
-
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(x-radius, y-radius)
-	screen.DrawImage(img, op)
-}
-
 // Функция для получения уникального цвета игрока
-func getPlayerColor(playerID int) color.Color {
-	// Задаём фиксированный набор цветов
-	colors := []color.Color{
-		color.RGBA{255, 0, 0, 255},   // Красный
-		color.RGBA{0, 255, 0, 255},   // Зеленый
-		color.RGBA{0, 0, 255, 255},   // Синий
-		color.RGBA{255, 255, 0, 255}, // Желтый
-		color.RGBA{255, 165, 0, 255}, // Оранжевый
-		color.RGBA{128, 0, 128, 255}, // Фиолетовый
-		// Добавьте больше цветов при необходимости
-	}
-
-	// Используем взятие остатка, чтобы ID игрока не превышал размер массива цветов
-	return colors[playerID%len(colors)]
+func getPlayerColor(playerID int) color.RGBA {
+	// Цвета общие со скинами, чтобы доспехи игрока совпадали с цветом его точек
+	return sprites.TeamColor(playerID)
 }
 
 func (l *Level1) Layout(outsideWidth, outsideHeight int) (int, int) {
@@ -0,0 +1,144 @@
+package level1
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	ringGradient = 10   // Ширина градиента внутрь круга, в пикселях
+	ringGlow     = 2    // Ширина свечения снаружи круга, в пикселях
+	ringBucket   = 1.05 // Во сколько раз соседние размеры текстур отличаются друг от друга
+	maxRings     = 32   // Сколько текстур колец держать; давно не нужные вытесняются
+
+	pulseFrequency = 1.5  // Пульсаций спорной точки в секунду
+	pulseScale     = 0.06 // Насколько кольцо спорной точки увеличивается в пике пульсации
+	pulseAlpha     = 0.35 // Насколько кольцо спорной точки бледнеет между пиками
+)
+
+// ringKey текстура кольца определяется ступенью радиуса в пикселях экрана и цветом.
+// Ступени растут в геометрической прогрессии, поэтому приближение камеры
+// порождает немного текстур, а каждая лишь чуть больше нужного размера.
+type ringKey struct {
+	bucket int
+	color  color.RGBA
+}
+
+// ringCache текстуры колец точек захвата. Кольцо с градиентом и свечением
+// рисуется один раз, а потом каждый кадр только копируется на экран.
+type ringCache struct {
+	images map[ringKey]*ebiten.Image
+	order  []ringKey // От давно использованных к недавним, для вытеснения
+}
+
+func newRingCache() *ringCache {
+	return &ringCache{images: make(map[ringKey]*ebiten.Image)}
+}
+
+// draw рисует кольцо радиуса radius (в координатах мира) с центром (x, y) на экране;
+// zoom переводит размер мира в пиксели экрана, scale дополнительно растягивает
+// кольцо (для растущего кольца прогресса и пульсации), alpha делает его прозрачнее
+func (c *ringCache) draw(screen *ebiten.Image, x, y, radius, zoom float64, clr color.RGBA, scale float64, alpha float32) {
+	if radius < 1 || zoom <= 0 || scale <= 0 {
+		return
+	}
+	// Текстура рисуется с радиусом не меньше экранного и только уменьшается,
+	// так что кольцо остается четким при любом приближении
+	pixels := radius * zoom
+	bucket := ringBucketOf(pixels)
+	img := c.get(bucket, clr)
+	half := float64(img.Bounds().Dx()) / 2
+	shrink := pixels / ringBucketRadius(bucket) * scale
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-half, -half)
+	op.GeoM.Scale(shrink, shrink)
+	op.GeoM.Translate(x, y)
+	op.ColorScale.ScaleAlpha(alpha)
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(img, op)
+}
+
+// ringBucketOf номер наименьшей ступени, радиус которой не меньше pixels
+func ringBucketOf(pixels float64) int {
+	return int(math.Ceil(math.Log(pixels) / math.Log(ringBucket)))
+}
+
+// ringBucketRadius радиус текстуры ступени bucket в пикселях
+func ringBucketRadius(bucket int) float64 {
+	return math.Pow(ringBucket, float64(bucket))
+}
+
+// get возвращает текстуру кольца, рисуя ее при первом обращении
+func (c *ringCache) get(bucket int, clr color.RGBA) *ebiten.Image {
+	key := ringKey{bucket, clr}
+	if img, ok := c.images[key]; ok {
+		c.touch(key)
+		return img
+	}
+	if len(c.order) >= maxRings {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		c.images[oldest].Deallocate()
+		delete(c.images, oldest)
+	}
+	img := renderRing(ringBucketRadius(bucket), clr)
+	c.images[key] = img
+	c.order = append(c.order, key)
+	return img
+}
+
+// touch переносит ключ в конец очереди вытеснения как только что использованный
+func (c *ringCache) touch(key ringKey) {
+	for i, k := range c.order {
+		if k == key {
+			copy(c.order[i:], c.order[i+1:])
+			c.order[len(c.order)-1] = key
+			return
+		}
+	}
+}
+
+// dispose освобождает все текстуры
+func (c *ringCache) dispose() {
+	for _, img := range c.images {
+		img.Deallocate()
+	}
+	clear(c.images)
+	c.order = nil
+}
+
+// renderRing рисует контур круга с градиентом внутрь и свечением наружу
+// на квадратной текстуре с кругом в центре
+func renderRing(radius float64, clr color.RGBA) *ebiten.Image {
+	size := int(math.Ceil(2 * (radius + ringGlow + 1)))
+	img := ebiten.NewImage(size, size)
+	center := float32(size) / 2
+	withAlpha := func(a uint8) color.RGBA {
+		return color.RGBA{clr.R, clr.G, clr.B, a}
+	}
+
+	// Градиент по краю круга
+	for r := radius; r > radius-ringGradient && r > 0; r-- {
+		vector.StrokeCircle(img, center, center, float32(r), 2, withAlpha(uint8(255*(r/radius))), true)
+	}
+
+	// Основной контур круга
+	vector.StrokeCircle(img, center, center, float32(radius), 1, clr, true)
+
+	// Свечение немного шире самого круга
+	glowRadius := radius + ringGlow
+	for r := radius; r < glowRadius; r++ {
+		vector.StrokeCircle(img, center, center, float32(r), 1, withAlpha(uint8(100*((glowRadius-r)/glowRadius))), true)
+	}
+	return img
+}
+
+// pulse возвращает увеличение и прозрачность кольца спорной точки в момент t (в секундах)
+func pulse(t float64) (scale float64, alpha float32) {
+	wave := (1 + math.Sin(2*math.Pi*pulseFrequency*t)) / 2 // От 0 до 1
+	return 1 + pulseScale*wave, float32(1 - pulseAlpha*(1-wave))
+}
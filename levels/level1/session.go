@@ -0,0 +1,490 @@
+package level1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"time"
+
+	input "github.com/quasilyte/ebitengine-input"
+	"main.go/collision"
+	"main.go/controls"
+	sprites "main.go/resourses/img"
+	"main.go/resourses/maps/tiled"
+)
+
+const (
+	DefaultServerAddress = "localhost:8080"
+	basePort             = 8089 // Локальный порт первого клиента, следующие игроки получают +1, +2...
+
+	handshakeTimeout = 5 * time.Second // Сколько ждать playerID от сервера
+	keepAlive        = time.Second     // Как часто напоминать серверу о себе, пока игрок стоит
+
+	// Хитбокс игрока, если в манифесте спрайтов он не задан
+	defaultHitboxWidth  = 20.0
+	defaultHitboxHeight = 28.0
+
+	// Сила тряски камеры от ударов (от 0 до 1)
+	actionImpact = 0.3  // Свой толчок или притягивание, в кадр удара
+	hurtImpact   = 0.6  // Урон по своему игроку
+	stepImpact   = 0.05 // Шаг своего игрока, едва заметное покачивание
+
+	// События кадров анимаций из манифеста спрайтов
+	eventHit      = "hit"
+	eventFootstep = "footstep"
+)
+
+// LocalPlayer описывает игрока, сидящего за этим компьютером
+type LocalPlayer struct {
+	Name   string
+	Skin   string
+	Keymap input.Keymap // Раскладка из пакета controls
+}
+
+// session отдельное подключение к серверу для одного локального игрока
+type session struct {
+	input         controls.Source
+	playerID      int
+	playerX       float64
+	playerY       float64
+	capturePoints []CapturePoint
+	players       []Player
+	FlipX         bool
+	Points        int
+	playerName    string
+	playerSkin    string
+	conn          *net.UDPConn
+	done          chan struct{}
+	lost          chan error     // Ошибка чтения от сервера, после которой обновлений больше не будет
+	states        chan GameState // Последнее непримененное состояние от сервера
+	lastUpdate    time.Time
+	serverAddr    *net.UDPAddr
+	world         *collision.World // Препятствия карты и другие игроки для предсказания движения
+	body          int              // Тело своего игрока в world
+	remoteBodies  map[int]int      // Тела других игроков по ID
+
+	animator        *sprites.Animator         // Анимация своего игрока
+	sentAnim        sprites.AnimationState    // Последнее состояние анимации, отправленное серверу
+	serverAnim      sprites.AnimationState    // Анимация своего игрока по данным сервера (урон, смерть)
+	lastServerAnim  sprites.AnimationState    // Уже обработанное значение serverAnim
+	remoteAnimators map[int]*sprites.Animator // Анимации других игроков по ID
+
+	impact float64 // Сила ударов с прошлого тика для тряски камеры
+
+	walkX, walkY float64 // Точка мира, к которой ведет указатель
+	walking      bool    // Указатель зажат в этом тике
+}
+
+// newSession настраивает UDP соединение и получает playerID от сервера
+func newSession(serverAddress string, localPort int, player LocalPlayer, src controls.Source, arena *tiled.Arena) (*session, error) {
+	serverAddr, err := net.ResolveUDPAddr("udp", serverAddress)
+	if err != nil {
+		return nil, fmt.Errorf("резолв адреса UDP: %w", err)
+	}
+	localAddr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("localhost:%d", localPort))
+	if err != nil {
+		return nil, fmt.Errorf("резолв адреса UDP: %w", err)
+	}
+	conn, err := net.DialUDP("udp", localAddr, serverAddr)
+	if err != nil {
+		return nil, fmt.Errorf("подключение к UDP серверу: %w", err)
+	}
+
+	s := &session{
+		input:        src,
+		conn:         conn,
+		serverAddr:   serverAddr,
+		world:        newArenaWorld(arena),
+		remoteBodies: make(map[int]int),
+		done:         make(chan struct{}),
+		lost:         make(chan error, 1),
+		states:       make(chan GameState, 1),
+		playerID:     0, // Пока ID неизвестен
+		playerName:   player.Name,
+		playerSkin:   player.Skin,
+		Points:       0,
+
+		animator:        sprites.NewAnimator(player.Skin),
+		remoteAnimators: make(map[int]*sprites.Animator),
+	}
+
+	// Получение playerID от сервера
+	if err := s.requestPlayerID(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// Игрок появляется в точке с карты, выбранной по его ID
+	spawn := arena.Spawn(s.playerID)
+	s.playerX, s.playerY = spawn.X, spawn.Y
+	s.body = s.world.Add(s.playerX, s.playerY, playerShape(player.Skin))
+	s.sendPositionUpdate()
+
+	go s.listenForUpdates()
+
+	return s, nil
+}
+
+func (s *session) requestPlayerID() error {
+	// Отправляем запрос на получение playerID
+	initialMsg := map[string]interface{}{
+		"request": "get_player_id",
+		"name":    s.playerName, // Передаем имя игрока
+		"skin":    s.playerSkin,
+	}
+	data, _ := json.Marshal(initialMsg)
+	if _, err := s.conn.Write(data); err != nil {
+		return fmt.Errorf("запрос playerID: %w", err)
+	}
+
+	// Ожидаем ответ от сервера с playerID, но не дольше handshakeTimeout
+	s.conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	defer s.conn.SetReadDeadline(time.Time{})
+	buffer := make([]byte, 2048)
+	n, _, err := s.conn.ReadFrom(buffer)
+	if err != nil {
+		return fmt.Errorf("сервер %s не ответил: %w", s.serverAddr, err)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(buffer[:n], &response); err != nil {
+		return fmt.Errorf("разбор ответа сервера: %w", err)
+	}
+
+	// Сохраняем playerID, полученный от сервера
+	if id, ok := response["id"].(float64); ok {
+		s.playerID = int(id)
+		s.animator.Tint = sprites.TeamColor(s.playerID)
+		slog.Info("Получен playerID", "id", s.playerID)
+	}
+	return nil
+}
+
+// close закрывает подключение; горутина приема обновлений завершится с ошибкой чтения
+func (s *session) close() {
+	s.conn.Close()
+}
+
+// listenForUpdates получает обновления от сервера
+func (s *session) listenForUpdates() {
+	buffer := make([]byte, 2048)
+	for {
+		n, _, err := s.conn.ReadFromUDP(buffer)
+		if errors.Is(err, net.ErrClosed) {
+			return // Подключение закрыто при выходе с уровня
+		}
+		if err != nil {
+			slog.Error("Ошибка при чтении данных от сервера", "err", err)
+			s.lost <- err
+			return
+		}
+
+		var gameState GameState
+		err = json.Unmarshal(buffer[:n], &gameState)
+		if err != nil {
+			slog.Warn("Ошибка при десериализации данных", "err", err)
+			continue
+		}
+
+		// Состояние применяется в основной горутине (см. applyUpdates); если прошлое
+		// еще не применено, оно уже устарело и заменяется новым
+		select {
+		case <-s.states:
+		default:
+		}
+		s.states <- gameState
+	}
+}
+
+// applyUpdates применяет последнее состояние, полученное от сервера с прошлого тика
+func (s *session) applyUpdates() {
+	select {
+	case state := <-s.states:
+		s.updateGameState(state)
+	default:
+	}
+}
+
+func (s *session) updateGameState(state GameState) {
+	s.players = state.Players
+	s.capturePoints = state.CapturePoints
+
+	// Обновляем координаты только для своего игрока
+	for i, player := range state.Players {
+		if player.ID == s.playerID {
+			s.Points = player.Points
+			s.serverAnim = player.Anim
+			// Сохраняем предыдущую позицию
+			continue
+		} else {
+			// Для других игроков, просто обновляем их предыдущие координаты
+			s.players[i].PrevX = s.players[i].X
+			s.players[i].PrevY = s.players[i].Y
+			s.players[i].LastUpdateTime = time.Now()
+			s.players[i].Name = player.Name // Обновляем имя
+			s.players[i].Skin = player.Skin // Обновляем скин
+			s.players[i].FlipX = player.FlipX
+
+		}
+
+	}
+	s.pruneRemoteAnimators()
+}
+
+// pruneRemoteAnimators забывает анимации игроков, которых нет в последнем состоянии сервера
+func (s *session) pruneRemoteAnimators() {
+	present := make(map[int]bool, len(s.players))
+	for _, p := range s.players {
+		present[p.ID] = true
+	}
+	for id := range s.remoteAnimators {
+		if !present[id] {
+			delete(s.remoteAnimators, id)
+		}
+	}
+}
+
+// update обрабатывает действия игрока через его обработчик ввода; dt - длительность тика
+func (s *session) update(dt time.Duration) {
+	s.applyUpdates()
+
+	speed := 10.0
+	originalX, originalY := s.playerX, s.playerY
+
+	var dx, dy float64
+	if s.input.ActionIsPressed(controls.ActionMoveUp) {
+		dy -= speed
+	}
+	if s.input.ActionIsPressed(controls.ActionMoveDown) {
+		dy += speed
+	}
+	// Спрайт отражается, пока игрок идет налево
+	s.FlipX = s.input.ActionIsPressed(controls.ActionMoveLeft)
+	if s.FlipX {
+		dx -= speed
+	}
+	if s.input.ActionIsPressed(controls.ActionMoveRight) {
+		dx += speed
+	}
+	// Без клавиш движения игрок идет к точке под указателем
+	if s.walking && dx == 0 && dy == 0 {
+		dx, dy = s.walkX-s.playerX, s.walkY-s.playerY
+		if dist := math.Hypot(dx, dy); dist > speed {
+			dx, dy = dx/dist*speed, dy/dist*speed
+		}
+		if dx != 0 {
+			s.FlipX = dx < 0
+		}
+	}
+	s.walking = false
+	// Стены и другие игроки останавливают движение, вдоль них игрок скользит.
+	// Двигаемся и без ввода, чтобы вытолкнуть игрока, если в него зашел другой.
+	s.syncRemoteBodies()
+	s.playerX, s.playerY = s.world.Move(s.body, dx, dy)
+
+	moved := originalX != s.playerX || originalY != s.playerY
+
+	if s.input.ActionIsPressed(controls.ActionPull) {
+		s.sendAction("pull")
+	}
+	if s.input.ActionIsPressed(controls.ActionPush) {
+		s.sendAction("push")
+	}
+
+	var action sprites.AnimationState
+	switch {
+	case s.input.ActionIsJustPressed(controls.ActionPull):
+		action = sprites.AnimAttack
+	case s.input.ActionIsJustPressed(controls.ActionPush):
+		action = sprites.AnimPush
+	}
+	s.updateAnimations(moved, action, dt)
+
+	// Если позиция или анимация изменились, отправляем данные на сервер
+	if moved || s.animator.State != s.sentAnim {
+		s.sendPositionUpdate()
+	}
+}
+
+// walkTo ведет игрока к точке мира (x, y) в ближайшем тике
+func (s *session) walkTo(x, y float64) {
+	s.walkX, s.walkY = x, y
+	s.walking = true
+}
+
+// newArenaWorld создает мир столкновений с препятствиями карты
+func newArenaWorld(arena *tiled.Arena) *collision.World {
+	world := collision.NewWorld(64)
+	for _, r := range arena.Colliders {
+		world.AddBox(collision.AABB{MinX: r.MinX, MinY: r.MinY, MaxX: r.MaxX, MaxY: r.MaxY})
+	}
+	return world
+}
+
+// playerShape возвращает хитбокс скина из манифеста спрайтов относительно точки привязки
+func playerShape(skin string) collision.Shape {
+	if sheet, ok := sprites.Sheets[skin]; ok && sheet.Hitbox.W > 0 && sheet.Hitbox.H > 0 {
+		hb := sheet.Hitbox
+		return collision.Box(hb.X, hb.Y, hb.W, hb.H)
+	}
+	return collision.Box(-defaultHitboxWidth/2, -defaultHitboxHeight/2, defaultHitboxWidth, defaultHitboxHeight)
+}
+
+// syncRemoteBodies переносит других игроков в мир столкновений по последним данным сервера
+func (s *session) syncRemoteBodies() {
+	present := make(map[int]bool, len(s.players))
+	for _, p := range s.players {
+		if p.ID == s.playerID {
+			continue
+		}
+		present[p.ID] = true
+		if body, ok := s.remoteBodies[p.ID]; ok {
+			s.world.SetShape(body, playerShape(p.Skin))
+			s.world.SetPosition(body, p.X, p.Y)
+		} else {
+			s.remoteBodies[p.ID] = s.world.Add(p.X, p.Y, playerShape(p.Skin))
+		}
+	}
+	// Отключившиеся игроки больше не мешают
+	for id, body := range s.remoteBodies {
+		if !present[id] {
+			s.world.Remove(body)
+			delete(s.remoteBodies, id)
+		}
+	}
+}
+
+// idle обновляет сессию без ввода (игра на паузе): анимации других игроков идут,
+// а сервер периодически получает текущую позицию, чтобы не счел игрока отключившимся
+func (s *session) idle(dt time.Duration) {
+	s.applyUpdates()
+	s.updateAnimations(false, "", dt)
+	if time.Since(s.lastUpdate) > keepAlive {
+		s.sendPositionUpdate()
+	}
+}
+
+// takeImpact возвращает силу ударов, накопленную с прошлого вызова
+func (s *session) takeImpact() float64 {
+	impact := s.impact
+	s.impact = 0
+	return impact
+}
+
+// checkConnection возвращает ошибку, если прием обновлений от сервера прекратился
+func (s *session) checkConnection() error {
+	select {
+	case err := <-s.lost:
+		return fmt.Errorf("соединение с сервером потеряно: %w", err)
+	default:
+		return nil
+	}
+}
+
+// updateAnimations переключает анимации своего игрока и других игроков;
+// action - одноразовое действие своего игрока в этом тике
+func (s *session) updateAnimations(moved bool, action sprites.AnimationState, dt time.Duration) {
+	// Урон, смерть и возрождение определяет сервер
+	if s.serverAnim != s.lastServerAnim {
+		s.lastServerAnim = s.serverAnim
+		switch s.serverAnim {
+		case sprites.AnimHurt, sprites.AnimDeath:
+			action = s.serverAnim
+			s.impact += hurtImpact
+		default:
+			s.animator.Respawn()
+		}
+	}
+	s.animator.Update(moved, action)
+	for _, event := range s.animator.Advance(dt) {
+		switch event {
+		case eventHit:
+			s.impact += actionImpact
+		case eventFootstep:
+			s.impact += stepImpact
+		}
+	}
+
+	for _, p := range s.players {
+		if p.ID == s.playerID {
+			continue
+		}
+		animator := s.remoteAnimator(p)
+		if p.Anim != "" {
+			animator.Play(p.Anim)
+		} else {
+			// Сервер без поддержки анимаций: определяем бег по смене позиции
+			animator.Update(p.X != p.PrevX || p.Y != p.PrevY, "")
+		}
+		animator.Advance(dt)
+	}
+}
+
+// remoteAnimator возвращает аниматор другого игрока, создавая его при первом обращении
+func (s *session) remoteAnimator(p Player) *sprites.Animator {
+	animator, ok := s.remoteAnimators[p.ID]
+	if !ok {
+		animator = sprites.NewAnimator(p.Skin)
+		s.remoteAnimators[p.ID] = animator
+	}
+	animator.Skin = p.Skin
+	animator.Tint = sprites.TeamColor(p.ID)
+	return animator
+}
+
+func (s *session) sendPositionUpdate() {
+	if time.Since(s.lastUpdate) > 10*time.Millisecond {
+		// Формируем данные для отправки
+		data := map[string]interface{}{
+			"id":    s.playerID,
+			"x":     s.playerX,
+			"y":     s.playerY,
+			"flipX": s.FlipX, // Добавляем состояние FlipX
+			"anim":  s.animator.State,
+		}
+
+		// Сериализуем данные в JSON
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			slog.Error("Ошибка сериализации данных", "err", err)
+			return
+		}
+
+		// Отправляем сериализованные данные через UDP
+		_, err = s.conn.Write(jsonData)
+		if err != nil {
+			slog.Warn("Ошибка отправки данных через UDP", "err", err)
+			return
+		}
+
+		// Обновляем время последней отправки
+		s.lastUpdate = time.Now()
+		s.sentAnim = s.animator.State
+	}
+}
+
+func (s *session) sendAction(action string) {
+	slog.Debug("Действие игрока", "id", s.playerID, "action", action)
+	// Формируем данные для отправки
+	data := map[string]interface{}{
+		"id":     s.playerID,
+		"action": action,
+	}
+
+	// Сериализуем данные в JSON
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		slog.Error("Ошибка сериализации данных", "err", err)
+		return
+	}
+
+	// Отправляем данные через UDP
+	_, err = s.conn.Write(jsonData)
+	if err != nil {
+		slog.Warn("Ошибка отправки данных через UDP", "err", err)
+		return
+	}
+}
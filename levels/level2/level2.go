@@ -1,51 +1,65 @@
 package level2
 
 import (
+	"image"
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	input "github.com/quasilyte/ebitengine-input"
+	"main.go/controls"
+	"main.go/scenes"
+	"main.go/ui"
 )
 
 type GameInterface interface {
-	SwitchLevel(level int)
-	GetScale() float64 // Метод для получения масштаба
+	SwitchScene(name string) // Имя из пакета scenes
+	SwitchSceneWith(name string, transition scenes.Transition)
+	NewInputSource(playerID uint8, keymap input.Keymap) controls.Source
 }
 
 type Level2 struct {
-	game GameInterface
+	game   GameInterface
+	input  controls.Source
+	button *ui.Button // Кнопка перехода, положение задается в Draw
 }
 
 func New(game GameInterface) *Level2 {
-	return &Level2{game: game}
+	return &Level2{
+		game:   game,
+		input:  game.NewInputSource(0, controls.DefaultKeymap),
+		button: ui.NewButton("Go to Level 5", color.RGBA{0, 255, 0, 255}),
+	}
 }
 
 func (l *Level2) Update() error {
 	// Пример: переход на уровень 5 при нажатии на Enter
-	if ebiten.IsKeyPressed(ebiten.KeyEnter) {
-		l.game.SwitchLevel(5)
+	if l.input.ActionIsJustPressed(controls.ActionConfirm) {
+		l.game.SwitchSceneWith(scenes.Level5, scenes.Wipe)
+	}
+	// Переход по нажатию кнопки мышью или касанием
+	if l.button.Clicked(l.input) {
+		l.game.SwitchSceneWith(scenes.Level5, scenes.Wipe)
 	}
 	return nil
 }
 
 func (l *Level2) Draw(screen *ebiten.Image) {
-	scale := l.game.GetScale()
-	screenWidth, screenHeight := ebiten.WindowSize()
+	// Размер берем из самого экрана, чтобы координаты кнопки совпадали с координатами указателя
+	screenWidth, screenHeight := screen.Bounds().Dx(), screen.Bounds().Dy()
 
-	// Размер и позиция кнопки с учетом масштабирования
-	buttonWidth := int(200 * scale)
-	buttonHeight := int(50 * scale)
+	// Размер и позиция кнопки в координатах виртуального экрана
+	buttonWidth := 200
+	buttonHeight := 50
 	buttonX := (screenWidth - buttonWidth) / 2
 	buttonY := (screenHeight - buttonHeight) / 2
 
 	// Отрисовка текста уровня
 	ebitenutil.DebugPrint(screen, "Level 2")
 
-	// Отрисовка кнопки (простого прямоугольника) с учетом масштабирования
-	ebitenutil.DrawRect(screen, float64(buttonX), float64(buttonY), float64(buttonWidth), float64(buttonHeight), color.RGBA{0, 255, 0, 255})
-
-	// Отрисовка текста на кнопке
-	ebitenutil.DebugPrintAt(screen, "Go to Level 5", buttonX+10, buttonY+buttonHeight/2-10)
+	// Отрисовка кнопки
+	l.button.Rect = image.Rect(buttonX, buttonY, buttonX+buttonWidth, buttonY+buttonHeight)
+	l.button.Draw(screen)
 }
 
 func (l *Level2) Layout(outsideWidth, outsideHeight int) (int, int) {
@@ -2,99 +2,189 @@ package menu
 
 import (
 	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
 	"time"
+	"unicode"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"main.go/controls"
 	"main.go/levels/level1"
 	sprites "main.go/resourses/img"
+	"main.go/scenes"
+	"main.go/ui"
 )
 
+const maxNameLength = 20 // Максимальная длина имени в символах
+
+var (
+	skinButtonColor         = color.RGBA{60, 60, 60, 255}
+	selectedSkinButtonColor = color.RGBA{0, 120, 200, 255}
+)
+
+// GameInterface дополняет интерфейс уровня настройкой второго локального игрока
+type GameInterface interface {
+	level1.GameInterface
+	SetSecondPlayerInfo(name, skin string)
+}
+
 type Menu struct {
-	game              level1.GameInterface // Интерфейс для переключения уровней
+	game              GameInterface   // Интерфейс для переключения уровней
+	input             controls.Source // Действия игрока (живой ввод или запись)
 	Player            *level1.Player
-	cursorIndex       int       // Индекс текущего поля для ввода (0 - имя, 1 - скин)
-	ready             bool      // Флаг, показывающий, что ввод завершен
-	skinOptions       []string  // Список доступных скинов
-	selectedSkinIndex int       // Индекс выбранного скина
-	enterPressedTime  time.Time // Время последнего нажатия Enter для предотвращения мгновенного переключения
-	arrowPressedTime  time.Time // Время последнего нажатия стрелки для предотвращения многократного переключения
+	firstPlayer       *level1.Player         // Первый игрок, уже завершивший ввод (в режиме игры вдвоем)
+	splitScreen       bool                   // Игра вдвоем на разделенном экране
+	nameInput         *ui.TextInput          // Поле ввода имени игрока
+	cursorIndex       int                    // Индекс текущего поля для ввода (0 - имя, 1 - скин)
+	ready             bool                   // Флаг, показывающий, что ввод завершен
+	skinOptions       []string               // Список доступных скинов
+	selectedSkinIndex int                    // Индекс выбранного скина
+	skinButtons       []*ui.Button           // Кнопки выбора скина мышью или касанием
+	confirmButton     *ui.Button             // Кнопка, заменяющая Enter
+	settingsButton    *ui.Button             // Открывает настройки поверх меню
+	preview           sprites.AnimatedSprite // Проигрыватель превью выбранного скина
+	previewTeam       int                    // Цвет команды, в котором показано превью
 }
 
 // New инициализация меню
-func New(game level1.GameInterface) *Menu {
-	return &Menu{
+func New(game GameInterface) *Menu {
+	nameInput := ui.NewTextInput(maxNameLength)
+	nameInput.Filter = func(r rune) bool {
+		return !unicode.IsSpace(r) // Пробелы в имени не допускаются
+	}
+
+	m := &Menu{
 		game:              game,
+		input:             game.NewInputSource(0, controls.MenuKeymap),
 		Player:            &level1.Player{},
-		skinOptions:       []string{"01Knight", "02Knight", "03Knight", "04Knight", "05Knight", "06Knight", "07Knight", "08Knight", "09Knight", "10Knight"},
-		selectedSkinIndex: 0,          // По умолчанию выбран первый скин
-		enterPressedTime:  time.Now(), // Инициализация времени последнего нажатия Enter
-		arrowPressedTime:  time.Now(), // Инициализация времени последнего нажатия стрелок
+		nameInput:         nameInput,
+		skinOptions:       append([]string(nil), sprites.Skins...), // Скины из манифеста спрайтов
+		selectedSkinIndex: 0,                                       // По умолчанию выбран первый скин
+		confirmButton:     ui.NewButton("OK", color.RGBA{0, 160, 0, 255}),
+		settingsButton:    ui.NewButton("Settings", skinButtonColor),
+	}
+	// Список скинов справа от превью
+	for _, skin := range m.skinOptions {
+		m.skinButtons = append(m.skinButtons, ui.NewButton(skin, skinButtonColor))
+	}
+	m.layout()
+	return m
+}
+
+// layout располагает кнопки в координатах виртуального экрана
+func (m *Menu) layout() {
+	m.confirmButton.Rect = image.Rect(700, 30, 860, 60)
+	m.settingsButton.Rect = image.Rect(880, 30, 1040, 60)
+	for i, b := range m.skinButtons {
+		b.Rect = image.Rect(700, 80+i*30, 860, 104+i*30)
 	}
 }
 
 func (m *Menu) Update() error {
+	m.preview.Update(time.Second / time.Duration(ebiten.TPS()))
+
+	if m.settingsButton.Clicked(m.input) {
+		m.game.PushScene(scenes.Settings)
+		return nil
+	}
+
 	// Убедимся, что ввод завершен
 	if !m.ready {
-		// Проверяем завершение ввода имени и скина, задержка для Enter
-		if ebiten.IsKeyPressed(ebiten.KeyEnter) && time.Since(m.enterPressedTime) > 300*time.Millisecond {
-			m.enterPressedTime = time.Now() // Обновляем время нажатия Enter
+		// Переключение режима игры вдвоем, пока первый игрок не закончил ввод
+		if m.input.ActionIsJustPressed(controls.ActionSplitScreen) && m.firstPlayer == nil {
+			m.splitScreen = !m.splitScreen
+		}
 
-			if m.cursorIndex == 1 && m.selectedSkinIndex >= 0 {
+		// Выбор скина щелчком по списку; с готовым именем сразу переходим к скину
+		for i, b := range m.skinButtons {
+			if b.Clicked(m.input) {
+				m.selectedSkinIndex = i
+				if m.cursorIndex == 0 && m.nameInput.Len() > 0 && m.nameInput.Valid() {
+					m.cursorIndex = 1
+					m.nameInput.Focused = false
+				}
+			}
+		}
+
+		// Проверяем завершение ввода имени и скина
+		if m.input.ActionIsJustPressed(controls.ActionConfirm) || m.confirmButton.Clicked(m.input) {
+			if m.cursorIndex == 1 && m.selectedSkin() != "" {
 				// Завершаем выбор скина и переходим к игре
-				m.Player.Skin = m.skinOptions[m.selectedSkinIndex]
-				m.ready = true
-			} else if m.cursorIndex == 0 && len(m.Player.Name) > 0 {
+				m.Player.Skin = m.selectedSkin()
+				if m.splitScreen && m.firstPlayer == nil {
+					// Первый игрок готов, переходим к вводу данных второго
+					m.firstPlayer = m.Player
+					m.Player = &level1.Player{}
+					m.nameInput.SetText("")
+					m.nameInput.Focused = true
+					m.cursorIndex = 0
+				} else {
+					m.ready = true
+				}
+			} else if m.cursorIndex == 0 && m.nameInput.Len() > 0 && m.nameInput.Valid() {
 				// Переход к выбору скина после ввода имени
 				m.cursorIndex = 1
+				m.nameInput.Focused = false
 			}
 		}
 
-		// Удаление символов (Backspace)
-		if ebiten.IsKeyPressed(ebiten.KeyBackspace) && m.cursorIndex == 0 && len(m.Player.Name) > 0 {
-			m.Player.Name = m.Player.Name[:len(m.Player.Name)-1]
-		}
-
 		// Ввод имени
 		if m.cursorIndex == 0 {
-			for _, char := range ebiten.InputChars() {
-				if char == ' ' || char == '\n' || char == '\t' {
-					continue // Игнорируем пробелы и спец. символы
-				}
-				if len(m.Player.Name) < 20 {
-					m.Player.Name += string(char)
-				}
+			if err := m.nameInput.Update(m.input); err != nil {
+				slog.Warn("Ошибка работы с буфером обмена", "err", err)
 			}
+			m.Player.Name = m.nameInput.Text()
 		}
 
-		// Выбор скина с помощью стрелок, с задержкой на повторное нажатие
+		// Выбор скина с помощью стрелок, по одному скину за нажатие
 		if m.cursorIndex == 1 {
-			if time.Since(m.arrowPressedTime) > 300*time.Millisecond { // Задержка 300 мс между переключениями
-				if ebiten.IsKeyPressed(ebiten.KeyArrowUp) && m.selectedSkinIndex > 0 {
-					m.selectedSkinIndex--
-					m.arrowPressedTime = time.Now() // Обновляем время последнего нажатия стрелки
-				} else if ebiten.IsKeyPressed(ebiten.KeyArrowDown) && m.selectedSkinIndex < len(m.skinOptions)-1 {
-					m.selectedSkinIndex++
-					m.arrowPressedTime = time.Now() // Обновляем время последнего нажатия стрелки
-				}
+			if m.input.ActionIsJustPressed(controls.ActionMoveUp) && m.selectedSkinIndex > 0 {
+				m.selectedSkinIndex--
+			} else if m.input.ActionIsJustPressed(controls.ActionMoveDown) && m.selectedSkinIndex < len(m.skinOptions)-1 {
+				m.selectedSkinIndex++
+			}
+
+			// Цвет команды назначает сервер, поэтому в меню его можно только посмотреть
+			if m.input.ActionIsJustPressed(controls.ActionMoveLeft) {
+				m.previewTeam = (m.previewTeam + len(sprites.TeamColors) - 1) % len(sprites.TeamColors)
+			} else if m.input.ActionIsJustPressed(controls.ActionMoveRight) {
+				m.previewTeam = (m.previewTeam + 1) % len(sprites.TeamColors)
 			}
 		}
 	} else {
 		// Если ввод завершён, передаем имя и скин игрока и переключаем на игру
-		m.game.SetPlayerInfo(m.Player.Name, m.Player.Skin)
-		m.game.SwitchLevel(1)
+		if m.firstPlayer != nil {
+			m.game.SetPlayerInfo(m.firstPlayer.Name, m.firstPlayer.Skin)
+			m.game.SetSecondPlayerInfo(m.Player.Name, m.Player.Skin)
+		} else {
+			m.game.SetPlayerInfo(m.Player.Name, m.Player.Skin)
+			m.game.SetSecondPlayerInfo("", "")
+		}
+		m.game.SwitchScene(scenes.Level1)
 	}
 
 	return nil
 }
 
+// selectedSkin возвращает выбранный скин или пустую строку, если скинов нет
+func (m *Menu) selectedSkin() string {
+	if m.selectedSkinIndex < 0 || m.selectedSkinIndex >= len(m.skinOptions) {
+		return ""
+	}
+	return m.skinOptions[m.selectedSkinIndex]
+}
+
 // Draw отвечает за отрисовку меню
 func (m *Menu) Draw(screen *ebiten.Image) {
-
 	// Отображение текста для имени
 	var nameText string
 	if m.cursorIndex == 0 {
-		nameText = fmt.Sprintf("Enter Name: %s|", m.Player.Name)
+		nameText = fmt.Sprintf("Enter Name: %s", m.nameInput.Display())
+		if err := m.nameInput.Err(); err != nil {
+			nameText += " (" + err.Error() + ")"
+		}
 	} else {
 		nameText = fmt.Sprintf("Name: %s", m.Player.Name)
 	}
@@ -102,9 +192,9 @@ func (m *Menu) Draw(screen *ebiten.Image) {
 	// Отображение текста для выбора скина
 	var skinText string
 	if m.cursorIndex == 1 {
-		skinText = fmt.Sprintf("Select Skin: %s (use Up/Down to switch)", m.skinOptions[m.selectedSkinIndex])
+		skinText = fmt.Sprintf("Select Skin: %s (use Up/Down or click to switch, Left/Right to preview team colors)", m.selectedSkin())
 	} else {
-		skinText = fmt.Sprintf("Skin: %s", m.skinOptions[m.selectedSkinIndex])
+		skinText = fmt.Sprintf("Skin: %s", m.selectedSkin())
 	}
 
 	// Сообщение о готовности
@@ -113,13 +203,37 @@ func (m *Menu) Draw(screen *ebiten.Image) {
 		readyText = "Ready! Press Enter to start..."
 	}
 
+	// Режим игры вдвоем и номер игрока, который сейчас вводит данные
+	modeText := "F2: split-screen for 2 players: off"
+	if m.splitScreen {
+		modeText = "F2: split-screen for 2 players: on (keyboard + gamepad)"
+		if m.firstPlayer == nil {
+			modeText += "\nPlayer 1"
+		} else {
+			modeText += "\nPlayer 2"
+		}
+	}
+
 	// Отрисовка текста
-	ebitenutil.DebugPrint(screen, nameText+"\n"+skinText+"\n"+readyText)
+	ebitenutil.DebugPrint(screen, modeText+"\n"+nameText+"\n"+skinText+"\n"+readyText)
+
+	// Кнопки выбора скина, выбранный подсвечен
+	for i, b := range m.skinButtons {
+		b.Color = skinButtonColor
+		if i == m.selectedSkinIndex {
+			b.Color = selectedSkinButtonColor
+		}
+		b.Draw(screen)
+	}
+	m.confirmButton.Draw(screen)
+	m.settingsButton.Draw(screen)
 
 	// Отрисовка выбранного скина
-	if sprite, ok := sprites.Sprites[m.skinOptions[m.selectedSkinIndex]]; ok {
+	if animation, ok := sprites.Sprites[m.selectedSkin()]; ok {
+		m.preview.SetAnimation(animation)
+		m.preview.Tint = sprites.TeamColor(m.previewTeam)
 		op := &ebiten.DrawImageOptions{}
-		sprite.Draw(screen, 400, 300, 2.0, false, op) // Координаты и масштаб можно настроить
+		m.preview.Draw(screen, 400, 300, 2.0, false, op) // Координаты и масштаб можно настроить
 	}
 }
 
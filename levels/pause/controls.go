@@ -0,0 +1,59 @@
+package pause
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"main.go/controls"
+	"main.go/ui"
+)
+
+// controlsHelp подсказка по раскладке DefaultKeymap и раскладкам игры вдвоем
+const controlsHelp = `Controls
+
+Move:        Arrows / WASD / D-pad (player 2: left stick)
+Pull:        P / Gamepad A
+Push:        O / Gamepad B
+Pause:       Esc / Gamepad Start
+Zoom:        + and - / mouse wheel / Gamepad R1 and L1
+Mouse/touch: hold to walk towards the pointer
+Touch:       hold to steer with the on-screen joystick (-joystick)
+
+Split-screen: player 1 uses WASD, P, O, Esc, + and -; player 2 uses the gamepad`
+
+// Controls экран с подсказкой по управлению, открывается из паузы
+type Controls struct {
+	game  GameInterface
+	input controls.Source
+	back  *ui.ButtonList
+}
+
+func NewControls(game GameInterface) *Controls {
+	return &Controls{
+		game:  game,
+		input: game.NewInputSource(0, controls.DefaultKeymap),
+		back:  ui.NewButtonList("Back"),
+	}
+}
+
+func (c *Controls) Update() error {
+	if c.input.ActionIsJustPressed(controls.ActionPause) || c.back.Update(c.input) == 0 {
+		c.game.PopScene()
+	}
+	return nil
+}
+
+func (c *Controls) Draw(screen *ebiten.Image) {
+	dim(screen)
+
+	scale := c.game.UIScale()
+	bounds := screen.Bounds()
+	x := bounds.Min.X + int(100*scale)
+	y := bounds.Min.Y + int(100*scale)
+	ebitenutil.DebugPrintAt(screen, controlsHelp, x, y)
+	c.back.Layout(x, y+int(200*scale), int(240*scale), int(40*scale), 0)
+	c.back.Draw(screen)
+}
+
+func (c *Controls) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return outsideWidth, outsideHeight
+}
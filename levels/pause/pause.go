@@ -0,0 +1,91 @@
+package pause
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	input "github.com/quasilyte/ebitengine-input"
+	"main.go/controls"
+	"main.go/scenes"
+	"main.go/ui"
+)
+
+type GameInterface interface {
+	PushScene(name string)   // Имя из пакета scenes
+	PopScene()               // Вернуться к сцене под паузой
+	SwitchScene(name string) // Закрыть все сцены и открыть новую
+	UIScale() float64
+	NewInputSource(playerID uint8, keymap input.Keymap) controls.Source
+}
+
+// Пункты меню паузы
+const (
+	optionResume = iota
+	optionSettings
+	optionControls
+	optionDisconnect
+	optionQuit
+)
+
+// Pause меню паузы поверх уровня. Уровень под ним не получает ввод,
+// но продолжает получать обновления от сервера, поэтому подключение не рвется.
+type Pause struct {
+	game    GameInterface
+	input   controls.Source
+	options *ui.ButtonList
+}
+
+func New(game GameInterface) *Pause {
+	return &Pause{
+		game:    game,
+		input:   game.NewInputSource(0, controls.DefaultKeymap),
+		options: ui.NewButtonList("Resume", "Settings", "Controls", "Disconnect to Menu", "Quit"),
+	}
+}
+
+func (p *Pause) Update() error {
+	// Повторное нажатие паузы возвращает в игру
+	if p.input.ActionIsJustPressed(controls.ActionPause) {
+		p.game.PopScene()
+		return nil
+	}
+
+	switch p.options.Update(p.input) {
+	case optionResume:
+		p.game.PopScene()
+	case optionSettings:
+		p.game.PushScene(scenes.Settings)
+	case optionControls:
+		p.game.PushScene(scenes.Controls)
+	case optionDisconnect:
+		p.game.SwitchScene(scenes.Menu) // Уровень закроет подключение в OnExit
+	case optionQuit:
+		return ebiten.Termination
+	}
+	return nil
+}
+
+func (p *Pause) Draw(screen *ebiten.Image) {
+	dim(screen)
+
+	scale := p.game.UIScale()
+	bounds := screen.Bounds()
+	width, height := int(240*scale), int(40*scale)
+	x := bounds.Min.X + (bounds.Dx()-width)/2
+	y := bounds.Min.Y + bounds.Dy()/3
+	ebitenutil.DebugPrintAt(screen, "Paused", x, y-int(40*scale))
+	p.options.Layout(x, y, width, height, int(10*scale))
+	p.options.Draw(screen)
+}
+
+func (p *Pause) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return outsideWidth, outsideHeight
+}
+
+// dim затемняет сцену под окном
+func dim(screen *ebiten.Image) {
+	bounds := screen.Bounds()
+	vector.DrawFilledRect(screen, float32(bounds.Min.X), float32(bounds.Min.Y), float32(bounds.Dx()), float32(bounds.Dy()), color.RGBA{0, 0, 0, 160}, false)
+}
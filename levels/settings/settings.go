@@ -0,0 +1,198 @@
+package settings
+
+import (
+	"fmt"
+	"image/color"
+	"log/slog"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	input "github.com/quasilyte/ebitengine-input"
+	"main.go/config"
+	"main.go/controls"
+	"main.go/ui"
+)
+
+type GameInterface interface {
+	Settings() config.Settings
+	ApplySettings(s config.Settings) // Применить сразу, без перезапуска
+	SaveSettings() error
+	PopScene()
+	UIScale() float64
+	NewInputSource(playerID uint8, keymap input.Keymap) controls.Source
+}
+
+var (
+	windowSizes = [][2]int{{1280, 720}, {1600, 900}, {1920, 1080}, {2560, 1440}}
+	tpsOptions  = []int{30, 60, 120, 144}
+	fpsOptions  = []int{0, 30, 60, 120, 144} // 0 - без ограничения
+)
+
+const (
+	minUIScale  = 0.5
+	maxUIScale  = 2
+	uiScaleStep = 0.25
+)
+
+// option строка настроек: подпись со значением и изменение стрелками влево/вправо
+type option struct {
+	label  func(s config.Settings) string
+	change func(s *config.Settings, dir int)
+}
+
+var options = []option{
+	{
+		label: func(s config.Settings) string { return fmt.Sprintf("Window: %dx%d", s.WindowWidth, s.WindowHeight) },
+		change: func(s *config.Settings, dir int) {
+			i := cycle(indexOfSize(s.WindowWidth, s.WindowHeight), len(windowSizes), dir)
+			s.WindowWidth, s.WindowHeight = windowSizes[i][0], windowSizes[i][1]
+		},
+	},
+	{
+		label:  func(s config.Settings) string { return "Fullscreen: " + onOff(s.Fullscreen) },
+		change: func(s *config.Settings, dir int) { s.Fullscreen = !s.Fullscreen },
+	},
+	{
+		label:  func(s config.Settings) string { return "VSync: " + onOff(s.VSync) },
+		change: func(s *config.Settings, dir int) { s.VSync = !s.VSync },
+	},
+	{
+		label: func(s config.Settings) string { return fmt.Sprintf("Ticks per second: %d", s.TPS) },
+		change: func(s *config.Settings, dir int) {
+			s.TPS = tpsOptions[cycle(indexOf(tpsOptions, s.TPS), len(tpsOptions), dir)]
+		},
+	},
+	{
+		label: func(s config.Settings) string { return "FPS limit: " + fpsLabel(s.FPS) },
+		change: func(s *config.Settings, dir int) {
+			s.FPS = fpsOptions[cycle(indexOf(fpsOptions, s.FPS), len(fpsOptions), dir)]
+		},
+	},
+	{
+		label: func(s config.Settings) string { return fmt.Sprintf("UI scale: %.2f", s.UIScale) },
+		change: func(s *config.Settings, dir int) {
+			s.UIScale = math.Max(minUIScale, math.Min(maxUIScale, s.UIScale+float64(dir)*uiScaleStep))
+		},
+	},
+}
+
+// Settings экран настроек. Изменения применяются сразу, а в файл
+// записываются при выходе с экрана.
+type Settings struct {
+	game  GameInterface
+	input controls.Source
+	list  *ui.ButtonList // Строки настроек и кнопка "Back" последней
+}
+
+func New(game GameInterface) *Settings {
+	labels := make([]string, 0, len(options)+1)
+	for _, o := range options {
+		labels = append(labels, o.label(game.Settings()))
+	}
+	return &Settings{
+		game:  game,
+		input: game.NewInputSource(0, controls.DefaultKeymap),
+		list:  ui.NewButtonList(append(labels, "Back")...),
+	}
+}
+
+func (s *Settings) Update() error {
+	if s.input.ActionIsJustPressed(controls.ActionPause) {
+		s.game.PopScene()
+		return nil
+	}
+
+	clicked := s.list.Update(s.input)
+	if clicked == len(options) {
+		s.game.PopScene()
+		return nil
+	}
+
+	dir := 0
+	switch {
+	case clicked >= 0:
+		dir = 1 // Подтверждение или щелчок переключает значение вперед
+	case s.input.ActionIsJustPressed(controls.ActionMoveLeft):
+		dir = -1
+	case s.input.ActionIsJustPressed(controls.ActionMoveRight):
+		dir = 1
+	}
+	if dir != 0 && s.list.Selected < len(options) {
+		cfg := s.game.Settings()
+		options[s.list.Selected].change(&cfg, dir)
+		s.game.ApplySettings(cfg)
+	}
+	return nil
+}
+
+// OnExit сохраняет настройки, когда экран закрывают
+func (s *Settings) OnExit() {
+	if err := s.game.SaveSettings(); err != nil {
+		slog.Error("Ошибка сохранения настроек", "err", err)
+	}
+}
+
+func (s *Settings) Draw(screen *ebiten.Image) {
+	cfg := s.game.Settings()
+	for i, o := range options {
+		s.list.Buttons[i].Label = o.label(cfg)
+	}
+
+	// Закрываем окно, поверх которого открыты настройки (меню или паузу)
+	bounds := screen.Bounds()
+	vector.DrawFilledRect(screen, float32(bounds.Min.X), float32(bounds.Min.Y), float32(bounds.Dx()), float32(bounds.Dy()), color.RGBA{0, 0, 0, 220}, false)
+
+	scale := s.game.UIScale()
+	width, height := int(320*scale), int(36*scale)
+	x := bounds.Min.X + (bounds.Dx()-width)/2
+	y := bounds.Min.Y + int(80*scale)
+	ebitenutil.DebugPrintAt(screen, "Settings (Up/Down to select, Left/Right or Enter to change)", x, y-int(40*scale))
+	s.list.Layout(x, y, width, height, int(6*scale))
+	s.list.Draw(screen)
+}
+
+func (s *Settings) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return outsideWidth, outsideHeight
+}
+
+// cycle сдвигает индекс по кругу; неизвестное значение (-1) переходит к первому
+func cycle(i, n, dir int) int {
+	if i < 0 {
+		return 0
+	}
+	return (i + dir + n) % n
+}
+
+func indexOf(values []int, v int) int {
+	for i, x := range values {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexOfSize(w, h int) int {
+	for i, size := range windowSizes {
+		if size[0] == w && size[1] == h {
+			return i
+		}
+	}
+	return -1
+}
+
+func onOff(v bool) string {
+	if v {
+		return "on"
+	}
+	return "off"
+}
+
+func fpsLabel(fps int) string {
+	if fps <= 0 {
+		return "off"
+	}
+	return fmt.Sprint(fps)
+}
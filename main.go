@@ -1,17 +1,121 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
 	"github.com/hajimehoshi/ebiten/v2"
+	"main.go/config"
 	"main.go/gamestate"
+	"main.go/levels/level1"
+	"main.go/resourses"
+	sprites "main.go/resourses/img"
+	"main.go/scenes"
 )
 
 func main() {
+	recordPath := flag.String("record", "", "записать ввод в файл для воспроизведения")
+	replayPath := flag.String("replay", "", "воспроизвести ввод из файла вместо клавиатуры")
+	joystick := flag.Bool("joystick", false, "экранный джойстик для движения касанием или мышью")
+	assetsDir := flag.String("assets", resourses.DefaultOverrideDir, "каталог с ресурсами, заменяющими встроенные (пусто - только встроенные)")
+	dev := flag.Bool("dev", false, "режим разработки: перезагружать измененные ресурсы из каталога -assets")
+	configPath := flag.String("config", config.DefaultPath, "файл настроек окна и графики")
+
+	// Запуск без меню, например из скриптов тестирования
+	startScene := flag.String("scene", "", "начальная сцена: "+strings.Join(gamestate.StartScenes(), ", ")+" (по умолчанию menu, с -name - level1)")
+	server := flag.String("server", level1.DefaultServerAddress, "адрес UDP сервера игры")
+	name := flag.String("name", "", "имя игрока; вместе с ним меню пропускается")
+	skin := flag.String("skin", "", "скин игрока (по умолчанию первый из манифеста спрайтов)")
+	width := flag.Int("width", 0, "ширина окна (0 - из настроек); задается вместе с -height")
+	height := flag.Int("height", 0, "высота окна (0 - из настроек); задается вместе с -width")
+	fullscreen := flag.Bool("fullscreen", false, "полноэкранный режим")
+	logLevel := flag.String("log", "info", "подробность журнала: debug, info, warn, error или off")
+	flag.Parse()
+
+	if err := setupLogging(*logLevel); err != nil {
+		usageError("%v", err)
+	}
+
+	if (*width > 0) != (*height > 0) || *width < 0 || *height < 0 {
+		usageError("размер окна задается положительными -width и -height вместе")
+	}
+
+	settings, err := config.Load(*configPath)
+	if err != nil {
+		slog.Warn("Настройки по умолчанию", "err", err)
+	}
+	// Флаги меняют настройки только на этот запуск, пока их не сохранят в экране настроек
+	if *width > 0 && *height > 0 {
+		settings.WindowWidth, settings.WindowHeight = *width, *height
+	}
+	if *fullscreen {
+		settings.Fullscreen = true
+	}
+	settings.Apply()
+
+	resourses.SetOverrideDir(*assetsDir)
+
+	scene := *startScene
+	if scene == "" {
+		scene = scenes.Menu
+		if *name != "" {
+			scene = scenes.Level1
+		}
+	}
+	if starts := gamestate.StartScenes(); !slices.Contains(starts, scene) {
+		usageError("сцена %q не может быть начальной, доступны: %s", scene, strings.Join(starts, ", "))
+	}
+	// Уровню нужен игрок, которого обычно задает меню
+	if scene == scenes.Level1 && *name == "" {
+		usageError("для сцены %s укажите имя игрока флагом -name", scene)
+	}
+
+	playerSkin := *skin
+	if *name != "" || playerSkin != "" {
+		skins := manifestSkins()
+		switch {
+		case playerSkin == "" && len(skins) > 0:
+			playerSkin = skins[0]
+		case playerSkin != "" && !slices.Contains(skins, playerSkin):
+			usageError("неизвестный скин %q, доступны: %s", playerSkin, strings.Join(skins, ", "))
+		}
+	}
 
 	game := gamestate.NewGame()
-	game.SwitchLevel(2) // Начальный уровень
+	defer game.Close()
+	game.UseSettings(*configPath, settings)
+	game.SetVirtualJoystick(*joystick)
+	game.SetServerAddress(*server)
 
-	// Установка оконного режима
-	ebiten.SetWindowSize(1600, 900)
+	if *name != "" {
+		game.SetPlayerInfo(*name, playerSkin)
+	}
+
+	if *dev && *assetsDir != "" {
+		if err := game.EnableHotReload(*assetsDir); err != nil {
+			slog.Warn("Горячая перезагрузка ресурсов недоступна", "err", err)
+		}
+	}
+
+	if *replayPath != "" {
+		if err := game.StartReplay(*replayPath); err != nil {
+			fatal("Ошибка загрузки записи ввода", err)
+		}
+	} else if *recordPath != "" {
+		if err := game.StartRecording(*recordPath); err != nil {
+			fatal("Ошибка записи ввода", err)
+		}
+	}
+
+	game.SwitchScene(scene) // Начальная сцена
+
+	// Размер окна, полноэкранный режим и частота заданы настройками
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled) // Разрешаем изменение размера окна
 	ebiten.SetWindowTitle("Level Switcher with Loading Screen")
 
@@ -19,3 +123,53 @@ func main() {
 		panic(err)
 	}
 }
+
+// usageError сообщает о неверных флагах и завершает программу, как flag при ошибке разбора
+func usageError(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	flag.Usage()
+	os.Exit(2)
+}
+
+// fatal сообщает об ошибке запуска и завершает программу даже при отключенном журнале
+func fatal(msg string, err error) {
+	fmt.Fprintf(os.Stderr, "%s: %v\n", msg, err)
+	os.Exit(1)
+}
+
+// setupLogging задает наименьший уровень сообщений журнала; off отключает его.
+// debug дополнительно добавляет время с микросекундами и место вызова.
+func setupLogging(level string) error {
+	switch level {
+	case "off":
+		log.SetOutput(io.Discard)
+	case "debug":
+		log.SetFlags(log.LstdFlags | log.Lmicroseconds | log.Lshortfile)
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+	case "info":
+		slog.SetLogLoggerLevel(slog.LevelInfo)
+	case "warn":
+		slog.SetLogLoggerLevel(slog.LevelWarn)
+	case "error":
+		slog.SetLogLoggerLevel(slog.LevelError)
+	default:
+		return fmt.Errorf("неизвестный уровень журнала %q, допустимы debug, info, warn, error, off", level)
+	}
+	return nil
+}
+
+// manifestSkins возвращает скины из манифеста спрайтов в порядке описания
+func manifestSkins() []string {
+	manifest, err := sprites.LoadManifest(sprites.ManifestPath)
+	if err != nil {
+		slog.Error("Ошибка чтения манифеста спрайтов", "err", err)
+		return nil
+	}
+	var skins []string
+	for _, sheet := range manifest.Sheets {
+		if sheet.Skin {
+			skins = append(skins, sheet.Name)
+		}
+	}
+	return skins
+}
@@ -0,0 +1,87 @@
+// Package resourses дает доступ к ресурсам игры. Все ресурсы встроены в бинарник,
+// поэтому игру можно запускать из любого каталога. Каталог переопределения
+// (для модов и разработки) имеет приоритет: файл из него заменяет встроенный.
+package resourses
+
+import (
+	"embed"
+	"errors"
+	"image"
+	_ "image/png" // Декодер PNG для image.Decode
+	"io/fs"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Встроенные ресурсы; пути считаются от каталога resourses
+//
+//go:embed img/loadscreen.png img/sprites maps
+var embedded embed.FS
+
+// DefaultOverrideDir каталог переопределения по умолчанию: при запуске из корня
+// репозитория рабочие файлы подхватываются без пересборки
+const DefaultOverrideDir = "resourses"
+
+var assets fs.FS = embedded
+
+// SetOverrideDir задает каталог, файлы из которого имеют приоритет над встроенными.
+// Если каталога нет или dir пуст, используются только встроенные ресурсы.
+func SetOverrideDir(dir string) {
+	if dir == "" {
+		assets = embedded
+		return
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		assets = embedded
+		return
+	}
+	assets = overlayFS{top: os.DirFS(dir), base: embedded}
+}
+
+// FS возвращает файловую систему ресурсов с учетом переопределения
+func FS() fs.FS {
+	return assets
+}
+
+// Open открывает ресурс по пути вида "img/sprites/01Knight.png"
+func Open(name string) (fs.File, error) {
+	return assets.Open(name)
+}
+
+// ReadFile читает ресурс целиком
+func ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(assets, name)
+}
+
+// LoadImage загружает и декодирует изображение
+func LoadImage(name string) (*ebiten.Image, error) {
+	file, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return ebiten.NewImageFromImage(img), nil
+}
+
+// overlayFS ищет файл сначала в top, затем в base
+type overlayFS struct {
+	top  fs.FS
+	base fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	file, err := o.top.Open(name)
+	if err == nil {
+		return file, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return o.base.Open(name)
+}
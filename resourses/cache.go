@@ -0,0 +1,170 @@
+package resourses
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Кэш декодированных изображений со счетчиком ссылок. Каждый PNG декодируется
+// один раз, пока на него есть хотя бы одна ссылка, и выгружается из видеопамяти,
+// когда последняя ссылка освобождена.
+
+type cachedImage struct {
+	image *ebiten.Image
+	refs  int
+}
+
+var (
+	cacheMu sync.Mutex
+	images  = make(map[string]*cachedImage)
+	bundles = make(map[string]*bundle)
+)
+
+// AcquireImage возвращает изображение из кэша, загружая его при первом обращении,
+// и увеличивает счетчик ссылок. На каждый вызов нужен парный ReleaseImage.
+func AcquireImage(name string) (*ebiten.Image, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if entry, ok := images[name]; ok {
+		entry.refs++
+		return entry.image, nil
+	}
+	img, err := LoadImage(name)
+	if err != nil {
+		return nil, err
+	}
+	images[name] = &cachedImage{image: img, refs: 1}
+	return img, nil
+}
+
+// ReleaseImage освобождает ссылку; без ссылок текстура выгружается (Deallocate)
+func ReleaseImage(name string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	entry, ok := images[name]
+	if !ok {
+		return
+	}
+	entry.refs--
+	if entry.refs <= 0 {
+		entry.image.Deallocate()
+		delete(images, name)
+	}
+}
+
+// RefreshImage заново читает изображение, если оно есть в кэше (для горячей перезагрузки).
+// При неизменном размере текстура обновляется на месте, и все ее части (SubImage)
+// сразу показывают новые пиксели. Возвращает false, если изображения в кэше нет.
+func RefreshImage(name string) (bool, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	entry, ok := images[name]
+	if !ok {
+		return false, nil
+	}
+	img, err := LoadImage(name)
+	if err != nil {
+		return true, err
+	}
+	if img.Bounds() == entry.image.Bounds() {
+		entry.image.Clear()
+		entry.image.DrawImage(img, nil)
+		img.Deallocate()
+		return true, nil
+	}
+	entry.image.Deallocate()
+	entry.image = img
+	return true, nil
+}
+
+// CachedImage возвращает изображение из кэша, не меняя счетчик ссылок
+func CachedImage(name string) (*ebiten.Image, bool) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	entry, ok := images[name]
+	if !ok {
+		return nil, false
+	}
+	return entry.image, true
+}
+
+// CachedImages возвращает число изображений в кэше (для отладки утечек)
+func CachedImages() int {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return len(images)
+}
+
+// bundle именованная группа ресурсов, которую сцены объявляют как зависимость
+type bundle struct {
+	load   func() error
+	unload func()
+	refs   int
+}
+
+// RegisterBundle регистрирует группу ресурсов с функциями загрузки и выгрузки
+func RegisterBundle(name string, load func() error, unload func()) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	bundles[name] = &bundle{load: load, unload: unload}
+}
+
+// AcquireBundles загружает группы, которые еще не загружены, и увеличивает их счетчики.
+// При ошибке уже захваченные этим вызовом группы освобождаются.
+func AcquireBundles(names ...string) error {
+	for i, name := range names {
+		if err := acquireBundle(name); err != nil {
+			ReleaseBundles(names[:i]...)
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleaseBundles уменьшает счетчики групп и выгружает те, что больше никому не нужны
+func ReleaseBundles(names ...string) {
+	for _, name := range names {
+		cacheMu.Lock()
+		b, ok := bundles[name]
+		if !ok || b.refs == 0 {
+			cacheMu.Unlock()
+			continue
+		}
+		b.refs--
+		unload := b.refs == 0
+		cacheMu.Unlock()
+
+		// Выгрузка сама освобождает изображения, поэтому вызывается без блокировки
+		if unload && b.unload != nil {
+			b.unload()
+		}
+	}
+}
+
+func acquireBundle(name string) error {
+	cacheMu.Lock()
+	b, ok := bundles[name]
+	if !ok {
+		cacheMu.Unlock()
+		return fmt.Errorf("неизвестная группа ресурсов %q", name)
+	}
+	first := b.refs == 0
+	cacheMu.Unlock()
+
+	if first && b.load != nil {
+		if err := b.load(); err != nil {
+			return fmt.Errorf("группа ресурсов %s: %w", name, err)
+		}
+	}
+
+	cacheMu.Lock()
+	b.refs++
+	cacheMu.Unlock()
+	return nil
+}
@@ -0,0 +1,124 @@
+// Package anim отвечает за тайминги анимаций: какой кадр показывать
+// спустя заданное время. Пакет не зависит от ebiten, поэтому его можно
+// тестировать без видеокарты и окна.
+package anim
+
+import "time"
+
+// Mode способ проигрывания клипа
+type Mode int
+
+const (
+	Loop     Mode = iota // По кругу: 0, 1, 2, 0, 1, 2...
+	Once                 // Один раз с остановкой на последнем кадре
+	PingPong             // Туда и обратно: 0, 1, 2, 1, 0, 1...
+)
+
+// Clip описание анимации без изображений: длительность каждого кадра,
+// режим и события, срабатывающие при переходе на кадр
+type Clip struct {
+	Durations []time.Duration // Длительность каждого кадра
+	Mode      Mode
+	Events    map[int]string // Номер кадра -> имя события (например, "footstep" или "hit")
+}
+
+// Uniform возвращает одинаковые длительности для frames кадров
+func Uniform(frames int, d time.Duration) []time.Duration {
+	durations := make([]time.Duration, frames)
+	for i := range durations {
+		durations[i] = d
+	}
+	return durations
+}
+
+// Player проигрывает клип для одной сущности и хранит ее собственный кадр и время
+type Player struct {
+	Clip    *Clip
+	Frame   int           // Текущий кадр
+	elapsed time.Duration // Сколько времени показан текущий кадр
+	reverse bool          // Обратный ход в режиме PingPong
+	done    bool          // Клип Once доиграл до конца
+	events  []string      // Буфер событий, возвращаемый из Update
+}
+
+// SetClip переключает клип и запускает его сначала; тот же клип не перезапускается
+func (p *Player) SetClip(clip *Clip) {
+	if p.Clip == clip {
+		return
+	}
+	p.Clip = clip
+	p.Reset()
+}
+
+// Reset запускает клип с первого кадра
+func (p *Player) Reset() {
+	p.Frame = 0
+	p.elapsed = 0
+	p.reverse = false
+	p.done = false
+}
+
+// Done сообщает, что клип в режиме Once показал последний кадр положенное время
+func (p *Player) Done() bool {
+	return p.done
+}
+
+// Update продвигает анимацию на dt и возвращает события кадров, на которые
+// она перешла. Срез переиспользуется и действителен до следующего вызова.
+func (p *Player) Update(dt time.Duration) []string {
+	p.events = p.events[:0]
+	if p.Clip == nil || len(p.Clip.Durations) == 0 || p.done {
+		return p.events
+	}
+
+	// Клип мог замениться на более короткий (например, при перезагрузке ресурсов)
+	if p.Frame >= len(p.Clip.Durations) {
+		p.Reset()
+	}
+
+	p.elapsed += dt
+	for !p.done {
+		d := p.Clip.Durations[p.Frame]
+		if d <= 0 || p.elapsed < d {
+			break // Кадр с нулевой длительностью считаем бесконечным
+		}
+		p.elapsed -= d
+		p.advance()
+	}
+	return p.events
+}
+
+// advance переходит на следующий кадр согласно режиму
+func (p *Player) advance() {
+	last := len(p.Clip.Durations) - 1
+	next := p.Frame
+
+	switch p.Clip.Mode {
+	case Loop:
+		next = (p.Frame + 1) % (last + 1)
+	case Once:
+		if p.Frame == last {
+			p.done = true
+			p.elapsed = 0
+			return
+		}
+		next = p.Frame + 1
+	case PingPong:
+		if last == 0 {
+			return
+		}
+		if p.reverse && p.Frame == 0 || !p.reverse && p.Frame == last {
+			p.reverse = !p.reverse
+		}
+		if p.reverse {
+			next = p.Frame - 1
+		} else {
+			next = p.Frame + 1
+		}
+	}
+
+	p.Frame = next
+	if name, ok := p.Clip.Events[next]; ok {
+		p.events = append(p.events, name)
+	}
+}
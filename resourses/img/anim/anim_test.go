@@ -0,0 +1,145 @@
+package anim
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+func frames(p *Player, dt time.Duration, steps int) []int {
+	var got []int
+	for i := 0; i < steps; i++ {
+		p.Update(dt)
+		got = append(got, p.Frame)
+	}
+	return got
+}
+
+func TestLoop(t *testing.T) {
+	p := &Player{}
+	p.SetClip(&Clip{Durations: Uniform(3, 100*ms), Mode: Loop})
+
+	got := frames(p, 100*ms, 5)
+	want := []int{1, 2, 0, 1, 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("кадры = %v, ожидалось %v", got, want)
+	}
+	if p.Done() {
+		t.Fatal("зацикленный клип не должен заканчиваться")
+	}
+}
+
+func TestOnceStopsOnLastFrame(t *testing.T) {
+	p := &Player{}
+	p.SetClip(&Clip{Durations: Uniform(3, 100*ms), Mode: Once})
+
+	got := frames(p, 100*ms, 2)
+	if want := []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("кадры = %v, ожидалось %v", got, want)
+	}
+	if p.Done() {
+		t.Fatal("клип закончился раньше, чем последний кадр показан положенное время")
+	}
+
+	p.Update(100 * ms)
+	if !p.Done() || p.Frame != 2 {
+		t.Fatalf("после последнего кадра: done=%v, кадр %d; ожидалось окончание на кадре 2", p.Done(), p.Frame)
+	}
+	p.Update(time.Second)
+	if p.Frame != 2 {
+		t.Fatalf("законченный клип перешел на кадр %d", p.Frame)
+	}
+}
+
+func TestPingPong(t *testing.T) {
+	p := &Player{}
+	p.SetClip(&Clip{Durations: Uniform(3, 100*ms), Mode: PingPong})
+
+	got := frames(p, 100*ms, 6)
+	want := []int{1, 2, 1, 0, 1, 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("кадры = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestPerFrameDurations(t *testing.T) {
+	p := &Player{}
+	p.SetClip(&Clip{Durations: []time.Duration{50 * ms, 200 * ms}, Mode: Loop})
+
+	got := frames(p, 50*ms, 6)
+	want := []int{1, 1, 1, 1, 0, 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("кадры = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestSpeedDoesNotDependOnStepSize(t *testing.T) {
+	clip := &Clip{Durations: Uniform(8, 100*ms), Mode: Loop}
+
+	// Одна секунда при 60 и при 144 обновлениях в секунду
+	slow, fast := &Player{}, &Player{}
+	slow.SetClip(clip)
+	fast.SetClip(clip)
+	for i := 0; i < 60; i++ {
+		slow.Update(time.Second / 60)
+	}
+	for i := 0; i < 144; i++ {
+		fast.Update(time.Second / 144)
+	}
+	if slow.Frame != fast.Frame {
+		t.Fatalf("кадр при 60 TPS = %d, при 144 TPS = %d", slow.Frame, fast.Frame)
+	}
+}
+
+func TestLargeStepSkipsFrames(t *testing.T) {
+	p := &Player{}
+	p.SetClip(&Clip{Durations: Uniform(4, 100*ms), Mode: Loop})
+
+	p.Update(250 * ms)
+	if p.Frame != 2 {
+		t.Fatalf("кадр = %d, ожидался 2", p.Frame)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	p := &Player{}
+	p.SetClip(&Clip{
+		Durations: Uniform(4, 100*ms),
+		Mode:      Loop,
+		Events:    map[int]string{1: "footstep", 3: "footstep"},
+	})
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		got = append(got, p.Update(100*ms)...)
+	}
+	want := []string{"footstep", "footstep"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("события = %v, ожидалось %v", got, want)
+	}
+
+	// Пропуск нескольких кадров за один шаг не теряет события
+	p.Reset()
+	if got := p.Update(400 * ms); !reflect.DeepEqual(got, want) {
+		t.Fatalf("события при большом шаге = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestSetClipRestartsOnlyOnChange(t *testing.T) {
+	clip := &Clip{Durations: Uniform(3, 100*ms), Mode: Loop}
+	p := &Player{}
+	p.SetClip(clip)
+	p.Update(100 * ms)
+
+	p.SetClip(clip)
+	if p.Frame != 1 {
+		t.Fatalf("тот же клип перезапустился, кадр = %d", p.Frame)
+	}
+
+	p.SetClip(&Clip{Durations: Uniform(3, 100*ms), Mode: Loop})
+	if p.Frame != 0 {
+		t.Fatalf("новый клип не начался сначала, кадр = %d", p.Frame)
+	}
+}
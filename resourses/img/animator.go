@@ -0,0 +1,108 @@
+package sprites
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"main.go/resourses/img/anim"
+)
+
+// AnimationState имя анимации персонажа; передается по сети как есть
+type AnimationState string
+
+const (
+	AnimIdle   AnimationState = "idle"
+	AnimRun    AnimationState = "run"
+	AnimAttack AnimationState = "attack" // Притягивание (pull)
+	AnimPush   AnimationState = "push"   // Отталкивание (push)
+	AnimHurt   AnimationState = "hurt"
+	AnimDeath  AnimationState = "death"
+)
+
+// Animator переключает анимации персонажа по движению и действиям.
+// Одноразовые анимации (атака, урон) доигрываются до конца,
+// смерть остается на последнем кадре до возрождения (см. Respawn).
+type Animator struct {
+	Skin   string
+	State  AnimationState
+	Tint   color.Color    // Цвет команды, в который перекрашивается скин (nil - без перекраски)
+	sprite AnimatedSprite // Собственный проигрыватель, кадры общие с другими игроками
+}
+
+// NewAnimator создает аниматор в состоянии покоя
+func NewAnimator(skin string) *Animator {
+	return &Animator{Skin: skin, State: AnimIdle}
+}
+
+// Update выбирает анимацию: action - одноразовое действие этого тика
+// (пустое, если его нет), moving - двигается ли персонаж. Повторное
+// действие запускает свою анимацию сначала.
+func (a *Animator) Update(moving bool, action AnimationState) {
+	if a.State == AnimDeath {
+		return
+	}
+	if action != "" {
+		if a.State == action {
+			a.sprite.Reset()
+			return
+		}
+		a.Play(action)
+		return
+	}
+	if a.busy() {
+		return // Даем доиграть атаке или урону
+	}
+	if moving {
+		a.Play(AnimRun)
+	} else {
+		a.Play(AnimIdle)
+	}
+}
+
+// Play переключает анимацию; повторный вызов с тем же состоянием ее не перезапускает
+func (a *Animator) Play(state AnimationState) {
+	if a.State == state {
+		return
+	}
+	a.State = state
+	a.sprite.SetAnimation(a.animation())
+}
+
+// Respawn выводит персонажа из смерти в покой; живого не трогает
+func (a *Animator) Respawn() {
+	if a.State == AnimDeath {
+		a.Play(AnimIdle)
+	}
+}
+
+// Advance продвигает текущую анимацию на dt и возвращает события ее кадров
+func (a *Animator) Advance(dt time.Duration) []string {
+	// Скин мог смениться или загрузиться позже создания аниматора
+	a.sprite.SetAnimation(a.animation())
+	return a.sprite.Update(dt)
+}
+
+// Draw рисует текущий кадр текущей анимации
+func (a *Animator) Draw(screen *ebiten.Image, x, y, scale float64, flipX bool, op *ebiten.DrawImageOptions) {
+	a.sprite.SetAnimation(a.animation())
+	a.sprite.Tint = a.Tint
+	a.sprite.Draw(screen, x, y, scale, flipX, op)
+}
+
+// busy сообщает, что идет одноразовая анимация, которую нельзя прерывать движением
+func (a *Animator) busy() bool {
+	animation := a.animation()
+	return animation != nil && animation.Clip.Mode == anim.Once && a.sprite.Animation == animation && !a.sprite.Done()
+}
+
+func (a *Animator) animation() *Animation {
+	animations, ok := Animations[a.Skin]
+	if !ok {
+		return nil
+	}
+	if animation, ok := animations[a.State]; ok {
+		return animation
+	}
+	return animations[AnimIdle]
+}
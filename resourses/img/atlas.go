@@ -0,0 +1,89 @@
+package sprites
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"main.go/resourses/img/atlas"
+)
+
+// Atlas страницы, в которые упакованы кадры всех листов. Кадры с одной страницы
+// рисуются из одной текстуры, поэтому ebiten объединяет их в один вызов отрисовки.
+type Atlas struct {
+	Pages []*ebiten.Image
+}
+
+// buildAtlas копирует кадры на страницы атласа и возвращает их части (SubImage)
+// в том же порядке. Края каждого кадра вытягиваются на extrude пикселей, чтобы
+// при масштабировании фильтр не подмешивал пиксели соседних кадров.
+func buildAtlas(frames []*ebiten.Image, spec AtlasSpec) (*Atlas, []*ebiten.Image, error) {
+	packer := &atlas.Packer{
+		PageWidth:  spec.PageSize,
+		PageHeight: spec.PageSize,
+		Border:     spec.Extrude,
+		Padding:    spec.Padding,
+	}
+	sizes := make([]image.Point, len(frames))
+	for i, frame := range frames {
+		sizes[i] = frame.Bounds().Size()
+	}
+	placements, err := packer.Pack(sizes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a := &Atlas{}
+	for i := 0; i < packer.Pages(); i++ {
+		// Страницы атласа не должны попадать во внутренний атлас ebiten
+		page := ebiten.NewImageWithOptions(image.Rect(0, 0, spec.PageSize, spec.PageSize), &ebiten.NewImageOptions{Unmanaged: true})
+		a.Pages = append(a.Pages, page)
+	}
+
+	packed := make([]*ebiten.Image, len(frames))
+	for i, frame := range frames {
+		pl := placements[i]
+		page := a.Pages[pl.Page]
+		drawExtruded(page, frame, pl.X, pl.Y, spec.Extrude)
+		packed[i] = page.SubImage(image.Rectangle{Min: image.Pt(pl.X, pl.Y), Max: image.Pt(pl.X, pl.Y).Add(sizes[i])}).(*ebiten.Image)
+	}
+	return a, packed, nil
+}
+
+// drawExtruded рисует кадр в (x, y) и растягивает его крайние строки и столбцы наружу
+func drawExtruded(page, frame *ebiten.Image, x, y, extrude int) {
+	b := frame.Bounds()
+	w, h := b.Dx(), b.Dy()
+	draw := func(src image.Rectangle, dx, dy, sx, sy int) {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(float64(sx), float64(sy))
+		op.GeoM.Translate(float64(dx), float64(dy))
+		page.DrawImage(frame.SubImage(src).(*ebiten.Image), op)
+	}
+
+	draw(b, x, y, 1, 1)
+	if extrude <= 0 || w == 0 || h == 0 {
+		return
+	}
+	e := extrude
+	// Стороны
+	draw(image.Rect(b.Min.X, b.Min.Y, b.Max.X, b.Min.Y+1), x, y-e, 1, e)
+	draw(image.Rect(b.Min.X, b.Max.Y-1, b.Max.X, b.Max.Y), x, y+h, 1, e)
+	draw(image.Rect(b.Min.X, b.Min.Y, b.Min.X+1, b.Max.Y), x-e, y, e, 1)
+	draw(image.Rect(b.Max.X-1, b.Min.Y, b.Max.X, b.Max.Y), x+w, y, e, 1)
+	// Углы
+	draw(image.Rect(b.Min.X, b.Min.Y, b.Min.X+1, b.Min.Y+1), x-e, y-e, e, e)
+	draw(image.Rect(b.Max.X-1, b.Min.Y, b.Max.X, b.Min.Y+1), x+w, y-e, e, e)
+	draw(image.Rect(b.Min.X, b.Max.Y-1, b.Min.X+1, b.Max.Y), x-e, y+h, e, e)
+	draw(image.Rect(b.Max.X-1, b.Max.Y-1, b.Max.X, b.Max.Y), x+w, y+h, e, e)
+}
+
+// Dispose освобождает страницы атласа
+func (a *Atlas) Dispose() {
+	if a == nil {
+		return
+	}
+	for _, page := range a.Pages {
+		page.Deallocate()
+	}
+	a.Pages = nil
+}
@@ -0,0 +1,100 @@
+package atlas
+
+import (
+	"fmt"
+	"image"
+	"sort"
+)
+
+// Placement положение прямоугольника в атласе: страница и левый верхний угол содержимого
+type Placement struct {
+	Page int
+	X, Y int
+}
+
+// Packer раскладывает прямоугольники по страницам полками: элементы ставятся
+// в ряд слева направо, а когда ряд заполнен, начинается новая полка ниже.
+// Вокруг каждого элемента оставляется Border пикселей (под вытягивание краев),
+// между элементами - еще Padding.
+type Packer struct {
+	PageWidth  int
+	PageHeight int
+	Border     int
+	Padding    int
+
+	pages []shelfPage
+}
+
+type shelfPage struct {
+	x, y   int // Позиция следующего элемента на текущей полке
+	height int // Высота текущей полки
+}
+
+// Pack раскладывает прямоугольники заданных размеров; результат в том же порядке, что sizes.
+// Высокие элементы ставятся первыми, так полки заполняются плотнее.
+func (p *Packer) Pack(sizes []image.Point) ([]Placement, error) {
+	order := make([]int, len(sizes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sizes[order[a]].Y > sizes[order[b]].Y
+	})
+
+	placements := make([]Placement, len(sizes))
+	for _, i := range order {
+		pl, err := p.Add(sizes[i].X, sizes[i].Y)
+		if err != nil {
+			return nil, err
+		}
+		placements[i] = pl
+	}
+	return placements, nil
+}
+
+// Add ставит один прямоугольник w x h, при необходимости открывая новую страницу
+func (p *Packer) Add(w, h int) (Placement, error) {
+	cellW := w + 2*p.Border
+	cellH := h + 2*p.Border
+	if cellW > p.PageWidth || cellH > p.PageHeight {
+		return Placement{}, fmt.Errorf("элемент %dx%d не помещается на страницу %dx%d", w, h, p.PageWidth, p.PageHeight)
+	}
+
+	for i := range p.pages {
+		if x, y, ok := p.pages[i].place(cellW, cellH, p); ok {
+			return Placement{Page: i, X: x + p.Border, Y: y + p.Border}, nil
+		}
+	}
+
+	p.pages = append(p.pages, shelfPage{})
+	i := len(p.pages) - 1
+	x, y, _ := p.pages[i].place(cellW, cellH, p)
+	return Placement{Page: i, X: x + p.Border, Y: y + p.Border}, nil
+}
+
+// Pages возвращает число открытых страниц
+func (p *Packer) Pages() int {
+	return len(p.pages)
+}
+
+// place ищет место под ячейку w x h; страница меняется только при успехе
+func (s *shelfPage) place(w, h int, p *Packer) (x, y int, ok bool) {
+	next := *s
+	// Не помещается в текущую полку по ширине или высоте - начинаем новую полку
+	if next.height > 0 && (next.x+w > p.PageWidth || h > next.height) {
+		next.y += next.height + p.Padding
+		next.x = 0
+		next.height = 0
+	}
+	if next.y+h > p.PageHeight || next.x+w > p.PageWidth {
+		return 0, 0, false
+	}
+
+	x, y = next.x, next.y
+	next.x += w + p.Padding
+	if h > next.height {
+		next.height = h
+	}
+	*s = next
+	return x, y, true
+}
@@ -0,0 +1,59 @@
+package atlas
+
+import (
+	"image"
+	"testing"
+)
+
+func TestPackNoOverlap(t *testing.T) {
+	p := &Packer{PageWidth: 128, PageHeight: 128, Border: 1, Padding: 1}
+	sizes := make([]image.Point, 20)
+	for i := range sizes {
+		sizes[i] = image.Pt(30, 20+i%3*5)
+	}
+	placements, err := p.Pack(sizes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cells := make([]image.Rectangle, len(sizes))
+	for i, pl := range placements {
+		cell := image.Rect(pl.X-1, pl.Y-1, pl.X+sizes[i].X+1, pl.Y+sizes[i].Y+1)
+		if !cell.In(image.Rect(0, 0, 128, 128)) {
+			t.Fatalf("элемент %d за пределами страницы: %v", i, cell)
+		}
+		for j := 0; j < i; j++ {
+			if placements[j].Page == pl.Page && cells[j].Overlaps(cell) {
+				t.Fatalf("элементы %d и %d пересекаются: %v %v", j, i, cells[j], cell)
+			}
+		}
+		cells[i] = cell
+	}
+	if p.Pages() < 2 {
+		t.Fatalf("ожидалось несколько страниц, получено %d", p.Pages())
+	}
+}
+
+func TestAddTooLarge(t *testing.T) {
+	p := &Packer{PageWidth: 64, PageHeight: 64, Border: 1}
+	if _, err := p.Add(63, 10); err == nil {
+		t.Fatal("ожидалась ошибка для элемента шире страницы с учетом рамки")
+	}
+}
+
+func TestAddSameSizeFillsRow(t *testing.T) {
+	p := &Packer{PageWidth: 100, PageHeight: 100}
+	for i := 0; i < 4; i++ {
+		pl, err := p.Add(25, 25)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pl.Page != 0 || pl.X != i*25 || pl.Y != 0 {
+			t.Fatalf("элемент %d: %+v", i, pl)
+		}
+	}
+	pl, _ := p.Add(25, 25)
+	if pl.X != 0 || pl.Y != 25 {
+		t.Fatalf("ожидалась новая полка, получено %+v", pl)
+	}
+}
@@ -0,0 +1,152 @@
+package sprites
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"main.go/resourses"
+	"main.go/resourses/img/anim"
+)
+
+// ManifestPath путь к описанию всех листов спрайтов внутри ресурсов
+const ManifestPath = "img/sprites/manifest.json"
+
+// Manifest описание листов спрайтов и их анимаций.
+// Новый скин добавляется записью в manifest.json без изменения кода.
+type Manifest struct {
+	// Наборы анимаций, общие для листов с одинаковой раскладкой
+	AnimationSets map[string]map[AnimationState]AnimationSpec `json:"animationSets"`
+	Sheets        []SheetSpec                                 `json:"sheets"`
+	Atlas         AtlasSpec                                   `json:"atlas"`
+
+	dir string // Каталог манифеста, от него считаются пути листов
+}
+
+// AtlasSpec параметры упаковки кадров в атлас
+type AtlasSpec struct {
+	PageSize int `json:"pageSize"` // Сторона квадратной страницы в пикселях
+	Padding  int `json:"padding"`  // Пустые пиксели между кадрами
+	Extrude  int `json:"extrude"`  // На сколько пикселей вытягиваются края кадра
+}
+
+// defaultAtlasPageSize сторона страницы, если в манифесте она не задана
+const defaultAtlasPageSize = 2048
+
+// SheetSpec описание одного листа спрайтов
+type SheetSpec struct {
+	Name         string                           `json:"name"`
+	Path         string                           `json:"path"` // Относительно каталога манифеста
+	Grid         Grid                             `json:"grid"`
+	Pivot        Point                            `json:"pivot"`  // Точка привязки в долях кадра, {0.5, 0.5} - центр
+	Hitbox       Rect                             `json:"hitbox"` // В пикселях кадра относительно точки привязки
+	Skin         bool                             `json:"skin"`   // Показывать в меню выбора скина
+	AnimationSet string                           `json:"animationSet,omitempty"`
+	Animations   map[AnimationState]AnimationSpec `json:"animations,omitempty"` // Дополняют и переопределяют набор
+}
+
+// Grid раскладка кадров в листе
+type Grid struct {
+	Rows int `json:"rows"`
+	Cols int `json:"cols"`
+}
+
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type Rect struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	W float64 `json:"w"`
+	H float64 `json:"h"`
+}
+
+// AnimationSpec описание одной анимации в листе
+type AnimationSpec struct {
+	Row           int            `json:"row"`                 // Строка листа, с 1
+	Frames        []int          `json:"frames,omitempty"`    // Столбцы, с 0; по умолчанию вся строка
+	FrameDuration int            `json:"frameDuration"`       // Длительность кадра в миллисекундах
+	Durations     []int          `json:"durations,omitempty"` // Длительности отдельных кадров, мс
+	Mode          string         `json:"mode"`                // loop, once или pingpong
+	Events        map[int]string `json:"events,omitempty"`    // Номер кадра -> событие
+}
+
+// LoadManifest читает и проверяет манифест из ресурсов игры
+func LoadManifest(name string) (*Manifest, error) {
+	data, err := resourses.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	for _, sheet := range m.Sheets {
+		if sheet.Name == "" || sheet.Path == "" {
+			return nil, fmt.Errorf("%s: у листа должны быть name и path", name)
+		}
+		if sheet.Grid.Rows <= 0 || sheet.Grid.Cols <= 0 {
+			return nil, fmt.Errorf("%s: лист %s: некорректная сетка %dx%d", name, sheet.Name, sheet.Grid.Rows, sheet.Grid.Cols)
+		}
+		if sheet.AnimationSet != "" {
+			if _, ok := m.AnimationSets[sheet.AnimationSet]; !ok {
+				return nil, fmt.Errorf("%s: лист %s: неизвестный набор анимаций %q", name, sheet.Name, sheet.AnimationSet)
+			}
+		}
+	}
+	if m.Atlas.PageSize == 0 {
+		m.Atlas.PageSize = defaultAtlasPageSize
+	}
+	if m.Atlas.PageSize < 0 || m.Atlas.Padding < 0 || m.Atlas.Extrude < 0 {
+		return nil, fmt.Errorf("%s: некорректные параметры атласа %+v", name, m.Atlas)
+	}
+	m.dir = path.Dir(name)
+	return &m, nil
+}
+
+// SheetPath возвращает путь к изображению листа внутри ресурсов
+func (m *Manifest) SheetPath(sheet SheetSpec) string {
+	return path.Join(m.dir, sheet.Path)
+}
+
+// SheetAnimations собирает анимации листа: набор плюс собственные описания листа
+func (m *Manifest) SheetAnimations(sheet SheetSpec) map[AnimationState]AnimationSpec {
+	specs := make(map[AnimationState]AnimationSpec)
+	for state, spec := range m.AnimationSets[sheet.AnimationSet] {
+		specs[state] = spec
+	}
+	for state, spec := range sheet.Animations {
+		specs[state] = spec
+	}
+	return specs
+}
+
+// Clip переводит описание анимации в тайминги для frames кадров
+func (spec AnimationSpec) Clip(frames int) (anim.Clip, error) {
+	mode, err := parseMode(spec.Mode)
+	if err != nil {
+		return anim.Clip{}, err
+	}
+	durations := anim.Uniform(frames, time.Duration(spec.FrameDuration)*time.Millisecond)
+	for i, ms := range spec.Durations {
+		if i < frames {
+			durations[i] = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return anim.Clip{Durations: durations, Mode: mode, Events: spec.Events}, nil
+}
+
+func parseMode(mode string) (anim.Mode, error) {
+	switch mode {
+	case "", "loop":
+		return anim.Loop, nil
+	case "once":
+		return anim.Once, nil
+	case "pingpong":
+		return anim.PingPong, nil
+	}
+	return 0, fmt.Errorf("неизвестный режим анимации %q", mode)
+}
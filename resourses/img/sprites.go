@@ -1,72 +1,258 @@
 package sprites
 
 import (
+	"fmt"
 	"image"
-	"os"
+	"image/color"
+	"sort"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+	"main.go/resourses"
+	"main.go/resourses/img/anim"
 )
 
 type Sprite struct {
 	Image *ebiten.Image
 }
 
+// Animation описание анимации: кадры и тайминги. Общее для всех, кто ее показывает,
+// и никогда не меняется при отрисовке.
+type Animation struct {
+	Frames []*ebiten.Image // Список кадров анимации
+	Clip   anim.Clip       // Длительности кадров, режим проигрывания и события
+	Pivot  Point           // Точка привязки в долях кадра
+}
+
+// Sheet загруженный лист спрайтов со всеми анимациями
+type Sheet struct {
+	Name       string
+	Pivot      Point
+	Hitbox     Rect // Относительно точки привязки, в пикселях кадра
+	Animations map[AnimationState]*Animation
+}
+
+// AnimatedSprite проигрыватель анимации для одной сущности:
+// у каждого игрока свой номер кадра и свое прошедшее время.
+// Кадры меняются в Update по прошедшему времени, Draw только рисует.
 type AnimatedSprite struct {
-	Frames   []*ebiten.Image // Список кадров анимации
-	Current  int             // Индекс текущего кадра
-	Interval int             // Интервал смены кадров
-	Timer    int             // Таймер для смены кадров
+	Animation *Animation  // Что проигрывается
+	Tint      color.Color // Цвет команды для перекраски скина (nil - исходные цвета)
+	player    anim.Player
 }
 
 var (
-	PlayerSprite *AnimatedSprite
-	EnemySprite  *AnimatedSprite
-	Sprites      map[string]*AnimatedSprite = make(map[string]*AnimatedSprite) // Инициализируем карту
+	Sprites map[string]*Animation = make(map[string]*Animation) // Анимация покоя для превью скина
+	// Все анимации каждого скина по состояниям
+	Animations map[string]map[AnimationState]*Animation = make(map[string]map[AnimationState]*Animation)
+	Sheets     map[string]*Sheet                        = make(map[string]*Sheet) // Листы из манифеста по имени
+	Skins      []string                                                           // Скины для меню в порядке манифеста
 )
 
-func LoadSprites() error {
+// NewAnimatedSprite создает проигрыватель анимации с первого кадра
+func NewAnimatedSprite(animation *Animation) *AnimatedSprite {
+	return &AnimatedSprite{Animation: animation}
+}
+
+// Bundle имя группы ресурсов спрайтов для сцен, которым нужны листы
+const Bundle = "sprites"
 
-	// Массив для всех файлов спрайтов
-	knightFiles := []string{
-		"01Knight", "02Knight", "03Knight", "04Knight", "05Knight",
-		"06Knight", "07Knight", "08Knight", "09Knight", "10Knight",
+func init() {
+	resourses.RegisterBundle(Bundle, LoadSprites, UnloadSprites)
+}
+
+var (
+	loadedManifest *Manifest // Манифест последней загрузки, нужен для перезагрузки
+	loadedAtlas    *Atlas    // Страницы, на которых лежат кадры всех листов
+)
+
+// LoadSprites загружает все листы из манифеста и упаковывает их кадры в атлас.
+// Исходные изображения листов после упаковки освобождаются, а анимации
+// ссылаются на части страниц атласа, поэтому код отрисовки их не различает.
+func LoadSprites() error {
+	manifest, err := LoadManifest(ManifestPath)
+	if err != nil {
+		return err
 	}
 
-	// Цикл по всем файлам спрайтов
-	for _, knight := range knightFiles {
-		path := "resourses/img/sprites/" + knight + ".png"
-		sheet, err := loadSprite(path)
+	var (
+		skins  []string
+		images []string
+		sheets []*Sheet
+	)
+	// Исходные листы нужны только до упаковки
+	defer func() {
+		for _, name := range images {
+			resourses.ReleaseImage(name)
+		}
+	}()
+	for _, spec := range manifest.Sheets {
+		path := manifest.SheetPath(spec)
+		img, err := resourses.AcquireImage(path)
 		if err != nil {
-			return err
+			return fmt.Errorf("лист %s: %w", spec.Name, err)
 		}
+		images = append(images, path)
 
-		// Используем вторую строку для анимации бега (всего 8 кадров)
-		frames, err := sliceSpriteSheet(sheet.Image, 6, 8, 2) // Меняйте строку для других типов анимаций
+		sheet, err := loadSheet(manifest, spec, img)
 		if err != nil {
-			return err
+			return fmt.Errorf("лист %s: %w", spec.Name, err)
 		}
+		sheets = append(sheets, sheet)
+		if spec.Skin {
+			skins = append(skins, spec.Name)
+		}
+	}
 
-		// Создаем анимированный спрайт и добавляем его в карту Sprites
-		Sprites[knight] = &AnimatedSprite{
-			Frames:   frames,
-			Current:  0,
-			Interval: 30, // Задайте интервал анимации по вашему усмотрению
-			Timer:    0,
+	packedAtlas, err := packSheets(sheets, manifest.Atlas)
+	if err != nil {
+		return fmt.Errorf("атлас спрайтов: %w", err)
+	}
+	for _, sheet := range sheets {
+		installSheet(sheet)
+	}
+	// Прежние страницы больше не нужны: анимации обновлены на месте
+	loadedAtlas.Dispose()
+	loadedAtlas = packedAtlas
+	Skins = skins
+	loadedManifest = manifest
+
+	return nil
+}
+
+// packSheets переносит кадры всех листов в атлас
+func packSheets(sheets []*Sheet, spec AtlasSpec) (*Atlas, error) {
+	var (
+		frames     []*ebiten.Image
+		animations []*Animation
+	)
+	for _, sheet := range sheets {
+		for _, state := range sortedStates(sheet.Animations) {
+			animation := sheet.Animations[state]
+			frames = append(frames, animation.Frames...)
+			animations = append(animations, animation)
 		}
 	}
 
+	a, packed, err := buildAtlas(frames, spec)
+	if err != nil {
+		return nil, err
+	}
+	for _, animation := range animations {
+		n := len(animation.Frames)
+		animation.Frames, packed = packed[:n:n], packed[n:]
+	}
+	return a, nil
+}
+
+// sortedStates возвращает состояния по алфавиту, чтобы раскладка атласа не менялась от запуска к запуску
+func sortedStates(animations map[AnimationState]*Animation) []AnimationState {
+	states := make([]AnimationState, 0, len(animations))
+	for state := range animations {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i] < states[j] })
+	return states
+}
+
+// UnloadSprites забывает все листы и освобождает страницы атласа
+func UnloadSprites() {
+	loadedAtlas.Dispose()
+	loadedAtlas = nil
+	loadedManifest = nil
+	Skins = nil
+	clear(Sheets)
+	clear(Animations)
+	clear(Sprites)
+}
+
+// Reload перезагружает спрайты, если изменился манифест или один из листов;
+// changed - пути внутри ресурсов. Атлас собирается заново целиком, а уже
+// проигрываемые анимации подхватывают новые кадры без перезапуска уровня.
+// Если спрайты сейчас не загружены, перезагружать нечего.
+func Reload(changed []string) error {
+	if loadedManifest == nil {
+		return nil
+	}
+	for _, name := range changed {
+		if name == ManifestPath {
+			return LoadSprites()
+		}
+		for _, spec := range loadedManifest.Sheets {
+			if name == loadedManifest.SheetPath(spec) {
+				return LoadSprites()
+			}
+		}
+	}
 	return nil
 }
 
-func sliceSpriteSheet(sheet *ebiten.Image, rows, cols, targetRow int) ([]*ebiten.Image, error) {
+// installSheet регистрирует лист. Если лист с таким именем уже загружен,
+// его анимации обновляются на месте, чтобы проигрыватели, держащие
+// указатели на них, сразу показывали новые кадры.
+func installSheet(sheet *Sheet) {
+	if old, ok := Sheets[sheet.Name]; ok {
+		for state, animation := range sheet.Animations {
+			if current, ok := old.Animations[state]; ok {
+				*current = *animation
+				sheet.Animations[state] = current
+			}
+		}
+	}
+
+	Sheets[sheet.Name] = sheet
+	Animations[sheet.Name] = sheet.Animations
+	if idle, ok := sheet.Animations[AnimIdle]; ok {
+		Sprites[sheet.Name] = idle
+	}
+}
+
+// loadSheet нарезает изображение листа на анимации по манифесту
+func loadSheet(manifest *Manifest, spec SheetSpec, img *ebiten.Image) (*Sheet, error) {
+	sheet := &Sheet{
+		Name:       spec.Name,
+		Pivot:      spec.Pivot,
+		Hitbox:     spec.Hitbox,
+		Animations: make(map[AnimationState]*Animation),
+	}
+	for state, animSpec := range manifest.SheetAnimations(spec) {
+		frames, err := sliceSpriteSheet(img, spec.Grid.Rows, spec.Grid.Cols, animSpec.Row, animSpec.Frames)
+		if err != nil {
+			return nil, fmt.Errorf("анимация %s: %w", state, err)
+		}
+		clip, err := animSpec.Clip(len(frames))
+		if err != nil {
+			return nil, fmt.Errorf("анимация %s: %w", state, err)
+		}
+		sheet.Animations[state] = &Animation{Frames: frames, Clip: clip, Pivot: spec.Pivot}
+	}
+	return sheet, nil
+}
+
+// sliceSpriteSheet нарезает кадры строки targetRow (с 1); columns - нужные столбцы
+// (с 0), при пустом списке берется вся строка
+func sliceSpriteSheet(sheet *ebiten.Image, rows, cols, targetRow int, columns []int) ([]*ebiten.Image, error) {
 	frames := []*ebiten.Image{}
 	sheetWidth, sheetHeight := sheet.Size()
 
 	frameWidth := sheetWidth / cols
 	frameHeight := sheetHeight / rows
 
+	if targetRow < 1 || targetRow > rows {
+		return nil, fmt.Errorf("строка %d вне сетки из %d строк", targetRow, rows)
+	}
+	if len(columns) == 0 {
+		for col := 0; col < cols; col++ {
+			columns = append(columns, col)
+		}
+	}
+
 	// Обрезаем спрайты из целевой строки (targetRow), начиная с 0
-	for col := 0; col < cols; col++ {
+	for _, col := range columns {
+		if col < 0 || col >= cols {
+			return nil, fmt.Errorf("столбец %d вне сетки из %d столбцов", col, cols)
+		}
 		x := col * frameWidth
 		y := (targetRow - 1) * frameHeight // Индексация строк с 0, поэтому вычитаем 1
 
@@ -78,36 +264,50 @@ func sliceSpriteSheet(sheet *ebiten.Image, rows, cols, targetRow int) ([]*ebiten
 	return frames, nil
 }
 
-func loadSprite(path string) (*Sprite, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
+// SetAnimation переключает проигрыватель на другую анимацию и запускает ее сначала
+func (s *AnimatedSprite) SetAnimation(animation *Animation) {
+	if s.Animation == animation {
+		return
 	}
-	defer file.Close()
-
-	img, _, err := image.Decode(file)
-	if err != nil {
-		return nil, err
+	s.Animation = animation
+	if animation != nil {
+		s.player.SetClip(&animation.Clip)
+	} else {
+		s.player.SetClip(nil)
 	}
+}
+
+// Reset запускает анимацию с первого кадра
+func (s *AnimatedSprite) Reset() {
+	s.player.Reset()
+}
+
+// Done сообщает, что одноразовая анимация доиграла до конца
+func (s *AnimatedSprite) Done() bool {
+	return s.player.Done()
+}
 
-	ebitenImg := ebiten.NewImageFromImage(img)
-	return &Sprite{Image: ebitenImg}, nil
+// Frame возвращает индекс текущего кадра
+func (s *AnimatedSprite) Frame() int {
+	return s.player.Frame
+}
+
+// Update продвигает анимацию на dt и возвращает события пройденных кадров
+// (например, "footstep"). Срез действителен до следующего вызова.
+func (s *AnimatedSprite) Update(dt time.Duration) []string {
+	return s.player.Update(dt)
 }
 
 // Метод для отрисовки анимационного спрайта с учетом отражения и масштаба
 func (s *AnimatedSprite) Draw(screen *ebiten.Image, x, y, scale float64, flipX bool, op *ebiten.DrawImageOptions) {
-	s.Timer++
-	if s.Timer >= s.Interval {
-		s.Timer = 0
-		s.Current = (s.Current + 1) % len(s.Frames) // Переход к следующему кадру
+	if s.Animation == nil || len(s.Animation.Frames) == 0 {
+		return
 	}
+	frames := s.Animation.Frames
 
 	// Обнуляем матрицу перед каждым кадром, чтобы избежать накопления трансляций
 	op.GeoM.Reset()
 
-	// Масштабируем изображение по Y
-	op.GeoM.Scale(scale, scale)
-
 	// Проверяем направление для отражения по X
 	if flipX {
 		// Отражаем по X, то есть масштабируем по X в отрицательном направлении
@@ -118,7 +318,7 @@ func (s *AnimatedSprite) Draw(screen *ebiten.Image, x, y, scale float64, flipX b
 	}
 
 	// Получаем текущий кадр
-	frame := s.Frames[s.Current]
+	frame := frames[s.player.Frame%len(frames)]
 
 	// Получаем размеры текущего кадра
 	frameWidth, frameHeight := frame.Size()
@@ -128,17 +328,27 @@ func (s *AnimatedSprite) Draw(screen *ebiten.Image, x, y, scale float64, flipX b
 	scaledFrameHeight := float64(frameHeight) * scale
 
 	// Если изображение отражено, корректируем смещение по X
+	pivotX := s.Animation.Pivot.X
 	if flipX {
-		// Если отражено, сдвигаем изображение на его ширину
+		// Если отражено, сдвигаем изображение на его ширину, точка привязки тоже отражается
 		op.GeoM.Translate(scaledFrameWidth, 0)
+		pivotX = 1 - pivotX
 	}
 
-	// Центрируем спрайт относительно координат (x, y)
-	op.GeoM.Translate(-scaledFrameWidth/2, -scaledFrameHeight/2)
+	// Ставим точку привязки спрайта в координаты (x, y)
+	op.GeoM.Translate(-scaledFrameWidth*pivotX, -scaledFrameHeight*s.Animation.Pivot.Y)
 
 	// Перемещаем спрайт к заданным координатам x, y
 	op.GeoM.Translate(x, y)
 
-	// Рисуем текущий кадр
+	// Рисуем текущий кадр, перекрашивая его в цвет команды
+	if s.Tint != nil {
+		colorm.DrawImage(screen, frame, tintMatrix(s.Tint, TintStrength), &colorm.DrawImageOptions{
+			GeoM:   op.GeoM,
+			Blend:  op.Blend,
+			Filter: op.Filter,
+		})
+		return
+	}
 	screen.DrawImage(frame, op)
 }
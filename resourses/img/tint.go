@@ -0,0 +1,47 @@
+package sprites
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+)
+
+// TintStrength доля цвета команды в перекрашенном спрайте (0 - без перекраски, 1 - полностью)
+const TintStrength = 0.6
+
+// TeamColors цвета игроков: ими красятся скины и захваченные точки
+var TeamColors = []color.RGBA{
+	{255, 0, 0, 255},   // Красный
+	{0, 255, 0, 255},   // Зеленый
+	{0, 0, 255, 255},   // Синий
+	{255, 255, 0, 255}, // Желтый
+	{255, 165, 0, 255}, // Оранжевый
+	{128, 0, 128, 255}, // Фиолетовый
+}
+
+// TeamColor возвращает цвет игрока по его ID
+func TeamColor(playerID int) color.RGBA {
+	n := len(TeamColors)
+	return TeamColors[(playerID%n+n)%n]
+}
+
+// tintMatrix матрица перекраски: яркость пикселя умножается на цвет команды
+// и смешивается с исходным цветом. Светлые детали (доспехи) получают цвет
+// команды, темные контуры остаются темными, прозрачность не меняется.
+func tintMatrix(c color.Color, strength float64) colorm.ColorM {
+	r, g, b, _ := c.RGBA()
+	tint := [3]float64{float64(r) / 0xffff, float64(g) / 0xffff, float64(b) / 0xffff}
+	luma := [3]float64{0.299, 0.587, 0.114}
+
+	var m colorm.ColorM
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			v := strength * tint[i] * luma[j]
+			if i == j {
+				v += 1 - strength
+			}
+			m.SetElement(i, j, v)
+		}
+	}
+	return m
+}
@@ -0,0 +1,178 @@
+// Package maps загружает карты арены из ресурсов и рисует их тайловые слои.
+// Разбор файлов Tiled и разметка арены - в пакете maps/tiled.
+package maps
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"main.go/camera"
+	"main.go/resourses"
+	"main.go/resourses/maps/tiled"
+)
+
+// ArenaPath путь к карте арены первого уровня внутри ресурсов
+const ArenaPath = "maps/arena.tmj"
+
+// Bundle имя группы ресурсов карты арены
+const Bundle = "maps"
+
+func init() {
+	resourses.RegisterBundle(Bundle, LoadArena, UnloadArena)
+}
+
+// Arena загруженная карта арены (nil, пока группа Bundle не загружена)
+var Arena *Map
+
+// Map карта с нарезанными тайлами, готовая к отрисовке
+type Map struct {
+	*tiled.Map
+	Arena tiled.Arena // Точки появления, точки захвата и препятствия
+
+	path   string
+	tiles  map[uint32]*ebiten.Image // Изображение тайла по номеру без флагов
+	images []string                 // Взятые из кэша изображения наборов
+}
+
+// LoadArena загружает карту арены. При повторной загрузке карта обновляется
+// на месте, чтобы уровень сразу рисовал новую версию.
+func LoadArena() error {
+	m, err := Load(ArenaPath)
+	if err != nil {
+		return err
+	}
+	if Arena != nil {
+		Arena.Release()
+		*Arena = *m
+		return nil
+	}
+	Arena = m
+	return nil
+}
+
+// UnloadArena освобождает изображения карты арены
+func UnloadArena() {
+	if Arena != nil {
+		Arena.Release()
+		Arena = nil
+	}
+}
+
+// Reload перезагружает карту арены, если среди changed есть ее файл или
+// изображение одного из наборов: при смене размера кэш заменяет текстуру,
+// и нарезанные тайлы нужно взять заново
+func Reload(changed []string) error {
+	if Arena == nil {
+		return nil
+	}
+	for _, name := range changed {
+		if name == Arena.path || slices.Contains(Arena.images, name) {
+			return LoadArena()
+		}
+	}
+	return nil
+}
+
+// Load читает карту и нарезает тайлы из изображений ее наборов
+func Load(path string) (*Map, error) {
+	tm, err := tiled.Load(resourses.FS(), path)
+	if err != nil {
+		return nil, err
+	}
+	m := &Map{
+		Map:   tm,
+		Arena: tm.Arena(),
+		path:  path,
+		tiles: make(map[uint32]*ebiten.Image),
+	}
+	for _, ts := range tm.Tilesets {
+		img, err := resourses.AcquireImage(ts.Image)
+		if err != nil {
+			m.Release()
+			return nil, fmt.Errorf("набор тайлов %s: %w", ts.Name, err)
+		}
+		m.images = append(m.images, ts.Image)
+		for id := 0; id < ts.TileCount; id++ {
+			gid := ts.FirstGID + uint32(id)
+			if x, y, w, h, ok := ts.TileRect(gid); ok {
+				m.tiles[gid] = img.SubImage(image.Rect(x, y, x+w, y+h)).(*ebiten.Image)
+			}
+		}
+	}
+	return m, nil
+}
+
+// Release возвращает изображения наборов в кэш ресурсов
+func (m *Map) Release() {
+	for _, name := range m.images {
+		resourses.ReleaseImage(name)
+	}
+	m.images = nil
+}
+
+// DrawBelow рисует слои под игроками
+func (m *Map) DrawBelow(screen *ebiten.Image, cam *camera.Camera) {
+	for _, l := range m.Layers {
+		if !l.Properties.Bool(tiled.PropertyAbove) {
+			m.drawLayer(screen, l, cam)
+		}
+	}
+}
+
+// DrawAbove рисует слои поверх игроков (кроны деревьев, крыши)
+func (m *Map) DrawAbove(screen *ebiten.Image, cam *camera.Camera) {
+	for _, l := range m.Layers {
+		if l.Properties.Bool(tiled.PropertyAbove) {
+			m.drawLayer(screen, l, cam)
+		}
+	}
+}
+
+// drawLayer рисует видимые камере тайлы слоя
+func (m *Map) drawLayer(screen *ebiten.Image, l *tiled.Layer, cam *camera.Camera) {
+	if !l.Visible || l.Opacity <= 0 {
+		return
+	}
+	tw, th := float64(m.TileWidth), float64(m.TileHeight)
+
+	// Только клетки в обзоре камеры, с запасом в клетку на тряску и крупные тайлы
+	visible := cam.Visible()
+	minX := max(0, int(math.Floor((visible.MinX-l.OffsetX)/tw))-1)
+	minY := max(0, int(math.Floor((visible.MinY-l.OffsetY)/th))-1)
+	maxX := min(l.Width-1, int(math.Ceil((visible.MaxX-l.OffsetX)/tw))+1)
+	maxY := min(l.Height-1, int(math.Ceil((visible.MaxY-l.OffsetY)/th))+1)
+
+	scale := cam.Scale()
+	originX, originY := cam.WorldToScreen(0, 0)
+	op := &ebiten.DrawImageOptions{}
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			gid, flipH, flipV := tiled.GID(l.Tile(x, y))
+			tile, ok := m.tiles[gid]
+			if !ok {
+				continue
+			}
+			w, h := tile.Bounds().Dx(), tile.Bounds().Dy()
+
+			op.GeoM.Reset()
+			if flipH {
+				op.GeoM.Scale(-1, 1)
+				op.GeoM.Translate(float64(w), 0)
+			}
+			if flipV {
+				op.GeoM.Scale(1, -1)
+				op.GeoM.Translate(0, float64(h))
+			}
+			// Тайлы крупнее клетки в Tiled выравниваются по нижнему краю
+			op.GeoM.Translate(float64(x)*tw+l.OffsetX, float64(y+1)*th-float64(h)+l.OffsetY)
+			op.GeoM.Scale(scale, scale)
+			op.GeoM.Translate(originX, originY)
+			op.ColorScale.Reset()
+			op.ColorScale.ScaleAlpha(float32(l.Opacity))
+			screen.DrawImage(tile, op)
+		}
+	}
+}
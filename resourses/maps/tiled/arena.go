@@ -0,0 +1,128 @@
+package tiled
+
+import "math"
+
+// Типы объектов и свойства, которыми на карте размечается арена
+const (
+	ClassSpawn        = "spawn"         // Точка появления игрока
+	ClassCapturePoint = "capture_point" // Точка захвата: центр объекта, радиус из свойства radius или размера
+	ClassCollision    = "collision"     // Непроходимый прямоугольник
+
+	LayerCollision    = "collision" // Все объекты слоя с этим именем непроходимы
+	PropertyCollision = "collision" // Тайловый слой со свойством collision = true непроходим целиком
+	PropertyAbove     = "above"     // Тайловый слой рисуется поверх игроков
+	PropertyRadius    = "radius"
+
+	defaultCaptureRadius = 50.0
+)
+
+// Point точка в пикселях карты
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Rect прямоугольник в пикселях карты
+type Rect struct {
+	MinX float64 `json:"minX"`
+	MinY float64 `json:"minY"`
+	MaxX float64 `json:"maxX"`
+	MaxY float64 `json:"maxY"`
+}
+
+// Contains сообщает, лежит ли точка внутри прямоугольника
+func (r Rect) Contains(x, y float64) bool {
+	return x >= r.MinX && x < r.MaxX && y >= r.MinY && y < r.MaxY
+}
+
+// CapturePoint размещение точки захвата; поля совпадают с CapturePoint
+// в состоянии игры, поэтому сервер может заполнить их прямо из карты
+type CapturePoint struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Radius float64 `json:"radius"`
+}
+
+// Arena то, что из карты нужно игре помимо картинки
+type Arena struct {
+	Width, Height float64 // Размер карты в пикселях
+	Spawns        []Point
+	CapturePoints []CapturePoint
+	Colliders     []Rect
+}
+
+// Arena собирает точки появления, точки захвата и препятствия карты.
+// Непроходимые тайлы соседних клеток одной строки объединяются в один прямоугольник.
+func (m *Map) Arena() Arena {
+	w, h := m.PixelSize()
+	a := Arena{Width: w, Height: h}
+
+	for _, o := range m.Objects {
+		switch {
+		case o.Class == ClassSpawn:
+			a.Spawns = append(a.Spawns, Point{o.X + o.Width/2, o.Y + o.Height/2})
+		case o.Class == ClassCapturePoint:
+			radius := math.Max(o.Width, o.Height) / 2
+			if radius == 0 {
+				radius = defaultCaptureRadius
+			}
+			a.CapturePoints = append(a.CapturePoints, CapturePoint{
+				X:      o.X + o.Width/2,
+				Y:      o.Y + o.Height/2,
+				Radius: o.Properties.Float(PropertyRadius, radius),
+			})
+		case o.Class == ClassCollision || o.Layer == LayerCollision:
+			if o.Width > 0 && o.Height > 0 {
+				a.Colliders = append(a.Colliders, Rect{o.X, o.Y, o.X + o.Width, o.Y + o.Height})
+			}
+		}
+	}
+
+	tw, th := float64(m.TileWidth), float64(m.TileHeight)
+	for _, l := range m.Layers {
+		if !l.Properties.Bool(PropertyCollision) {
+			continue
+		}
+		for y := 0; y < l.Height; y++ {
+			for x := 0; x < l.Width; {
+				if l.Tile(x, y) == 0 {
+					x++
+					continue
+				}
+				start := x
+				for x < l.Width && l.Tile(x, y) != 0 {
+					x++
+				}
+				a.Colliders = append(a.Colliders, Rect{
+					MinX: float64(start)*tw + l.OffsetX,
+					MinY: float64(y)*th + l.OffsetY,
+					MaxX: float64(x)*tw + l.OffsetX,
+					MaxY: float64(y+1)*th + l.OffsetY,
+				})
+			}
+		}
+	}
+	return a
+}
+
+// Solid сообщает, занята ли точка препятствием
+func (a *Arena) Solid(x, y float64) bool {
+	for _, r := range a.Colliders {
+		if r.Contains(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// Spawn возвращает точку появления для игрока с номером n; без точек на карте -
+// центр арены
+func (a *Arena) Spawn(n int) Point {
+	if len(a.Spawns) == 0 {
+		return Point{a.Width / 2, a.Height / 2}
+	}
+	if n < 0 {
+		n = -n
+	}
+	return a.Spawns[n%len(a.Spawns)]
+}
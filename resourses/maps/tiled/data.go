@@ -0,0 +1,67 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// decodeBase64 разбирает данные слоя в кодировке base64, сжатые или нет:
+// по четыре байта little-endian на тайл
+func decodeBase64(text, compression string) ([]uint32, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, fmt.Errorf("base64: %w", err)
+	}
+
+	var r io.Reader = bytes.NewReader(raw)
+	switch compression {
+	case "":
+	case "zlib":
+		if r, err = zlib.NewReader(r); err != nil {
+			return nil, fmt.Errorf("zlib: %w", err)
+		}
+	case "gzip":
+		if r, err = gzip.NewReader(r); err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("сжатие %q не поддерживается, сохраните карту без сжатия, с zlib или gzip", compression)
+	}
+	raw, err = io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", compression, err)
+	}
+
+	if len(raw)%4 != 0 {
+		return nil, fmt.Errorf("длина данных %d не кратна 4", len(raw))
+	}
+	data := make([]uint32, len(raw)/4)
+	for i := range data {
+		data[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+	return data, nil
+}
+
+// decodeCSV разбирает данные слоя в кодировке csv
+func decodeCSV(text string) ([]uint32, error) {
+	var data []uint32
+	for _, field := range strings.Split(text, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		v, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("csv: %w", err)
+		}
+		data = append(data, uint32(v))
+	}
+	return data, nil
+}
@@ -0,0 +1,226 @@
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Структуры формата JSON; в общий Map переводятся функцией parseJSON
+
+type jsonMap struct {
+	Width       int            `json:"width"`
+	Height      int            `json:"height"`
+	TileWidth   int            `json:"tilewidth"`
+	TileHeight  int            `json:"tileheight"`
+	Orientation string         `json:"orientation"`
+	Infinite    bool           `json:"infinite"`
+	Layers      []jsonLayer    `json:"layers"`
+	Tilesets    []jsonTileset  `json:"tilesets"`
+	Properties  []jsonProperty `json:"properties"`
+}
+
+type jsonLayer struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Visible     *bool           `json:"visible"` // Без поля слой виден
+	Opacity     *float64        `json:"opacity"` // Без поля слой непрозрачен
+	OffsetX     float64         `json:"offsetx"`
+	OffsetY     float64         `json:"offsety"`
+	Data        json.RawMessage `json:"data"` // Массив номеров или строка base64
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Objects     []jsonObject    `json:"objects"`
+	Properties  []jsonProperty  `json:"properties"`
+}
+
+type jsonTileset struct {
+	FirstGID    uint32 `json:"firstgid"`
+	Source      string `json:"source"` // Внешний набор .tsj или .tsx
+	Name        string `json:"name"`
+	Image       string `json:"image"`
+	ImageWidth  int    `json:"imagewidth"`
+	ImageHeight int    `json:"imageheight"`
+	TileWidth   int    `json:"tilewidth"`
+	TileHeight  int    `json:"tileheight"`
+	Columns     int    `json:"columns"`
+	TileCount   int    `json:"tilecount"`
+	Margin      int    `json:"margin"`
+	Spacing     int    `json:"spacing"`
+}
+
+type jsonObject struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Class      string         `json:"class"` // Так поле называется в Tiled 1.9
+	X          float64        `json:"x"`
+	Y          float64        `json:"y"`
+	Width      float64        `json:"width"`
+	Height     float64        `json:"height"`
+	Ellipse    bool           `json:"ellipse"`
+	Point      bool           `json:"point"`
+	Properties []jsonProperty `json:"properties"`
+}
+
+type jsonProperty struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+// parseJSON читает карту в формате JSON; dir - каталог карты для путей изображений
+func parseJSON(data []byte, dir string, readTileset tilesetReader) (*Map, error) {
+	var jm jsonMap
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, err
+	}
+	if jm.Orientation != "" && jm.Orientation != "orthogonal" {
+		return nil, fmt.Errorf("ориентация %q не поддерживается, нужна orthogonal", jm.Orientation)
+	}
+	if jm.Infinite {
+		return nil, fmt.Errorf("бесконечные карты не поддерживаются")
+	}
+
+	m := &Map{
+		Width:      jm.Width,
+		Height:     jm.Height,
+		TileWidth:  jm.TileWidth,
+		TileHeight: jm.TileHeight,
+		Properties: jsonProperties(jm.Properties),
+	}
+	for _, jt := range jm.Tilesets {
+		if jt.Source != "" {
+			ts, err := readTileset(jt.Source)
+			if err != nil {
+				return nil, err
+			}
+			ts.FirstGID = jt.FirstGID
+			m.Tilesets = append(m.Tilesets, ts)
+			continue
+		}
+		ts := jt.tileset()
+		ts.Image = path.Join(dir, ts.Image)
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+	for _, jl := range jm.Layers {
+		switch jl.Type {
+		case "tilelayer":
+			layer, err := jl.tileLayer()
+			if err != nil {
+				return nil, fmt.Errorf("слой %s: %w", jl.Name, err)
+			}
+			m.Layers = append(m.Layers, layer)
+		case "objectgroup":
+			for _, jo := range jl.Objects {
+				m.Objects = append(m.Objects, jo.object(jl.Name))
+			}
+		case "group":
+			return nil, fmt.Errorf("слой %s: группы слоев не поддерживаются", jl.Name)
+		}
+		// Слои изображений не нужны арене и пропускаются
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// parseJSONTileset читает внешний набор тайлов в формате JSON (.tsj)
+func parseJSONTileset(data []byte) (*Tileset, error) {
+	var jt jsonTileset
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return nil, err
+	}
+	return jt.tileset(), nil
+}
+
+func (jt jsonTileset) tileset() *Tileset {
+	return &Tileset{
+		FirstGID:    jt.FirstGID,
+		Name:        jt.Name,
+		Image:       jt.Image,
+		ImageWidth:  jt.ImageWidth,
+		ImageHeight: jt.ImageHeight,
+		TileWidth:   jt.TileWidth,
+		TileHeight:  jt.TileHeight,
+		Columns:     jt.Columns,
+		TileCount:   jt.TileCount,
+		Margin:      jt.Margin,
+		Spacing:     jt.Spacing,
+	}
+}
+
+func (jl jsonLayer) tileLayer() (*Layer, error) {
+	layer := &Layer{
+		Name:       jl.Name,
+		Width:      jl.Width,
+		Height:     jl.Height,
+		Visible:    jl.Visible == nil || *jl.Visible,
+		Opacity:    1,
+		OffsetX:    jl.OffsetX,
+		OffsetY:    jl.OffsetY,
+		Properties: jsonProperties(jl.Properties),
+	}
+	if jl.Opacity != nil {
+		layer.Opacity = *jl.Opacity
+	}
+	switch jl.Encoding {
+	case "", "csv":
+		if err := json.Unmarshal(jl.Data, &layer.Data); err != nil {
+			return nil, err
+		}
+	case "base64":
+		var text string
+		if err := json.Unmarshal(jl.Data, &text); err != nil {
+			return nil, err
+		}
+		data, err := decodeBase64(text, jl.Compression)
+		if err != nil {
+			return nil, err
+		}
+		layer.Data = data
+	default:
+		return nil, fmt.Errorf("кодировка %q не поддерживается", jl.Encoding)
+	}
+	return layer, nil
+}
+
+func (jo jsonObject) object(layer string) Object {
+	class := jo.Type
+	if class == "" {
+		class = jo.Class
+	}
+	return Object{
+		ID:         jo.ID,
+		Name:       jo.Name,
+		Class:      class,
+		Layer:      layer,
+		X:          jo.X,
+		Y:          jo.Y,
+		Width:      jo.Width,
+		Height:     jo.Height,
+		Ellipse:    jo.Ellipse,
+		Point:      jo.Point,
+		Properties: jsonProperties(jo.Properties),
+	}
+}
+
+// jsonProperties переводит свойства в строки: строки без кавычек, остальное как в JSON
+func jsonProperties(props []jsonProperty) Properties {
+	if len(props) == 0 {
+		return nil
+	}
+	p := make(Properties, len(props))
+	for _, prop := range props {
+		var s string
+		if err := json.Unmarshal(prop.Value, &s); err == nil {
+			p[prop.Name] = s
+		} else {
+			p[prop.Name] = strings.TrimSpace(string(prop.Value))
+		}
+	}
+	return p
+}
@@ -0,0 +1,201 @@
+// Package tiled читает карты редактора Tiled в форматах JSON (.tmj, .json)
+// и TMX (.tmx) и достает из них описание арены: точки появления, точки
+// захвата и препятствия. Пакет не зависит от ebiten, поэтому тот же файл
+// карты может читать сервер игры.
+package tiled
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Флаги отражения в старших битах номера тайла
+const (
+	FlipHorizontal = 0x80000000
+	FlipVertical   = 0x40000000
+	FlipDiagonal   = 0x20000000
+	flipMask       = FlipHorizontal | FlipVertical | FlipDiagonal | 0x10000000 // Последний бит - поворот для шестиугольных карт
+)
+
+// Map карта с тайловыми слоями, наборами тайлов и объектами
+type Map struct {
+	Width, Height         int // В тайлах
+	TileWidth, TileHeight int // В пикселях
+	Layers                []*Layer
+	Tilesets              []*Tileset
+	Objects               []Object // Объекты всех слоев объектов по порядку
+	Properties            Properties
+}
+
+// Layer тайловый слой. Data - номера тайлов по строкам, 0 - пусто.
+type Layer struct {
+	Name             string
+	Width, Height    int
+	Data             []uint32 // Номера с флагами отражения, см. GID
+	Visible          bool
+	Opacity          float64
+	OffsetX, OffsetY float64
+	Properties       Properties
+}
+
+// Tileset набор тайлов из одного изображения
+type Tileset struct {
+	FirstGID              uint32
+	Name                  string
+	Image                 string // Путь внутри файловой системы карты
+	ImageWidth            int
+	ImageHeight           int
+	TileWidth, TileHeight int
+	Columns               int
+	TileCount             int
+	Margin, Spacing       int
+}
+
+// Object объект со слоя объектов
+type Object struct {
+	ID            int
+	Name          string
+	Class         string // Тип объекта ("type" или "class" в Tiled)
+	Layer         string // Имя слоя объектов
+	X, Y          float64
+	Width, Height float64
+	Ellipse       bool
+	Point         bool
+	Properties    Properties
+}
+
+// Properties пользовательские свойства; значения хранятся строками
+type Properties map[string]string
+
+// Bool возвращает логическое свойство, отсутствующее считается false
+func (p Properties) Bool(name string) bool {
+	v, _ := strconv.ParseBool(p[name])
+	return v
+}
+
+// Float возвращает числовое свойство или def, если его нет
+func (p Properties) Float(name string, def float64) float64 {
+	v, err := strconv.ParseFloat(p[name], 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// GID отделяет номер тайла от флагов отражения
+func GID(raw uint32) (gid uint32, flipH, flipV bool) {
+	return raw &^ flipMask, raw&FlipHorizontal != 0, raw&FlipVertical != 0
+}
+
+// Tile возвращает номер тайла слоя в клетке (x, y) с флагами отражения
+func (l *Layer) Tile(x, y int) uint32 {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return 0
+	}
+	return l.Data[y*l.Width+x]
+}
+
+// TilesetFor возвращает набор, которому принадлежит тайл gid
+func (m *Map) TilesetFor(gid uint32) *Tileset {
+	var found *Tileset
+	for _, ts := range m.Tilesets {
+		if gid >= ts.FirstGID && (found == nil || ts.FirstGID > found.FirstGID) {
+			found = ts
+		}
+	}
+	return found
+}
+
+// TileRect возвращает положение тайла gid на изображении его набора
+func (ts *Tileset) TileRect(gid uint32) (x, y, w, h int, ok bool) {
+	id := int(gid - ts.FirstGID)
+	if gid < ts.FirstGID || id >= ts.TileCount || ts.Columns <= 0 {
+		return 0, 0, 0, 0, false
+	}
+	col, row := id%ts.Columns, id/ts.Columns
+	x = ts.Margin + col*(ts.TileWidth+ts.Spacing)
+	y = ts.Margin + row*(ts.TileHeight+ts.Spacing)
+	return x, y, ts.TileWidth, ts.TileHeight, true
+}
+
+// PixelSize возвращает размер карты в пикселях
+func (m *Map) PixelSize() (float64, float64) {
+	return float64(m.Width * m.TileWidth), float64(m.Height * m.TileHeight)
+}
+
+// Load читает карту name из fsys. Формат определяется по расширению:
+// .tmx - XML, остальное - JSON. Пути наборов тайлов и изображений
+// считаются от каталога карты, как в Tiled.
+func Load(fsys fs.FS, name string) (*Map, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	dir := path.Dir(name)
+	readTileset := func(source string) (*Tileset, error) {
+		tsPath := path.Join(dir, source)
+		data, err := fs.ReadFile(fsys, tsPath)
+		if err != nil {
+			return nil, err
+		}
+		ts, err := parseTileset(data, tsPath)
+		if err != nil {
+			return nil, fmt.Errorf("набор тайлов %s: %w", source, err)
+		}
+		return ts, nil
+	}
+
+	var m *Map
+	if strings.EqualFold(path.Ext(name), ".tmx") {
+		m, err = parseTMX(data, dir, readTileset)
+	} else {
+		m, err = parseJSON(data, dir, readTileset)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("карта %s: %w", name, err)
+	}
+	return m, nil
+}
+
+// tilesetReader читает внешний набор тайлов по пути из карты
+type tilesetReader func(source string) (*Tileset, error)
+
+// parseTileset читает внешний набор: .tsx - XML, остальное - JSON.
+// Изображение набора сразу переводится в путь от корня файловой системы.
+func parseTileset(data []byte, tsPath string) (*Tileset, error) {
+	var (
+		ts  *Tileset
+		err error
+	)
+	if strings.EqualFold(path.Ext(tsPath), ".tsx") {
+		ts, err = parseTSX(data)
+	} else {
+		ts, err = parseJSONTileset(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ts.Image = path.Join(path.Dir(tsPath), ts.Image)
+	return ts, nil
+}
+
+// validate проверяет размеры карты и слоев после разбора любого формата
+func (m *Map) validate() error {
+	if m.Width <= 0 || m.Height <= 0 || m.TileWidth <= 0 || m.TileHeight <= 0 {
+		return fmt.Errorf("некорректный размер карты %dx%d, тайл %dx%d", m.Width, m.Height, m.TileWidth, m.TileHeight)
+	}
+	for _, l := range m.Layers {
+		if len(l.Data) != l.Width*l.Height {
+			return fmt.Errorf("слой %s: %d тайлов вместо %dx%d", l.Name, len(l.Data), l.Width, l.Height)
+		}
+	}
+	for _, ts := range m.Tilesets {
+		if ts.Columns <= 0 && ts.TileWidth > 0 {
+			ts.Columns = (ts.ImageWidth - 2*ts.Margin + ts.Spacing) / (ts.TileWidth + ts.Spacing)
+		}
+	}
+	return nil
+}
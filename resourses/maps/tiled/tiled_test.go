@@ -0,0 +1,206 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+const testJSON = `{
+  "width": 4, "height": 3, "tilewidth": 16, "tileheight": 16,
+  "orientation": "orthogonal",
+  "layers": [
+    {"type": "tilelayer", "name": "ground", "width": 4, "height": 3,
+     "data": [1, 1, 1, 1, 1, 2, 2, 1, 1, 1, 1, 2147483649]},
+    {"type": "tilelayer", "name": "walls", "width": 4, "height": 3, "visible": false,
+     "properties": [{"name": "collision", "type": "bool", "value": true}],
+     "data": [3, 3, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0]},
+    {"type": "objectgroup", "name": "objects", "objects": [
+      {"id": 1, "type": "spawn", "x": 8, "y": 40, "point": true},
+      {"id": 2, "class": "capture_point", "x": 16, "y": 16, "width": 32, "height": 32, "ellipse": true},
+      {"id": 3, "type": "capture_point", "x": 0, "y": 0, "width": 10, "height": 10,
+       "properties": [{"name": "radius", "type": "float", "value": 25}]}
+    ]},
+    {"type": "objectgroup", "name": "collision", "objects": [
+      {"id": 4, "x": 48, "y": 32, "width": 16, "height": 16}
+    ]}
+  ],
+  "tilesets": [
+    {"firstgid": 1, "name": "tiles", "image": "tiles.png", "imagewidth": 64, "imageheight": 16,
+     "tilewidth": 16, "tileheight": 16, "columns": 4, "tilecount": 4}
+  ]
+}`
+
+func TestLoadJSON(t *testing.T) {
+	fsys := fstest.MapFS{"maps/test.tmj": {Data: []byte(testJSON)}}
+	m, err := Load(fsys, "maps/test.tmj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Layers) != 2 || len(m.Objects) != 4 || len(m.Tilesets) != 1 {
+		t.Fatalf("слоев %d, объектов %d, наборов %d", len(m.Layers), len(m.Objects), len(m.Tilesets))
+	}
+	if m.Tilesets[0].Image != "maps/tiles.png" {
+		t.Fatalf("путь изображения %q, ожидался maps/tiles.png", m.Tilesets[0].Image)
+	}
+	if !m.Layers[0].Visible || m.Layers[0].Opacity != 1 || m.Layers[1].Visible {
+		t.Fatal("видимость и прозрачность слоев по умолчанию разобраны неверно")
+	}
+
+	gid, flipH, flipV := GID(m.Layers[0].Tile(3, 2))
+	if gid != 1 || !flipH || flipV {
+		t.Fatalf("GID = %d, flipH = %v, flipV = %v", gid, flipH, flipV)
+	}
+	x, y, w, h, ok := m.Tilesets[0].TileRect(3)
+	if !ok || x != 32 || y != 0 || w != 16 || h != 16 {
+		t.Fatalf("TileRect(3) = %d, %d, %d, %d, %v", x, y, w, h, ok)
+	}
+}
+
+func TestArena(t *testing.T) {
+	fsys := fstest.MapFS{"test.json": {Data: []byte(testJSON)}}
+	m, err := Load(fsys, "test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := m.Arena()
+	if a.Width != 64 || a.Height != 48 {
+		t.Fatalf("размер арены %vx%v", a.Width, a.Height)
+	}
+	if len(a.Spawns) != 1 || a.Spawns[0] != (Point{8, 40}) {
+		t.Fatalf("точки появления %v", a.Spawns)
+	}
+	want := []CapturePoint{{32, 32, 16}, {5, 5, 25}}
+	if len(a.CapturePoints) != 2 || a.CapturePoints[0] != want[0] || a.CapturePoints[1] != want[1] {
+		t.Fatalf("точки захвата %v, ожидались %v", a.CapturePoints, want)
+	}
+	// Объект слоя collision и два отрезка строки тайлов: [0, 2) и [3, 4)
+	if len(a.Colliders) != 3 {
+		t.Fatalf("препятствия %v", a.Colliders)
+	}
+	for _, p := range []Point{{1, 1}, {31, 15}, {50, 8}, {60, 40}} {
+		if !a.Solid(p.X, p.Y) {
+			t.Errorf("точка %v должна быть занята", p)
+		}
+	}
+	for _, p := range []Point{{40, 8}, {32, 32}, {8, 40}} {
+		if a.Solid(p.X, p.Y) {
+			t.Errorf("точка %v должна быть свободна", p)
+		}
+	}
+	if a.Spawn(5) != a.Spawns[0] {
+		t.Fatal("Spawn должен повторять точки по кругу")
+	}
+}
+
+func TestLoadTMX(t *testing.T) {
+	// Второй слой в base64 со сжатием zlib, набор тайлов во внешнем файле
+	var raw bytes.Buffer
+	for _, gid := range []uint32{0, 5, 5, 0} {
+		binary.Write(&raw, binary.LittleEndian, gid)
+	}
+	var packed bytes.Buffer
+	zw := zlib.NewWriter(&packed)
+	zw.Write(raw.Bytes())
+	zw.Close()
+
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="2" tilewidth="8" tileheight="8">
+ <tileset firstgid="1" source="sets/tiles.tsx"/>
+ <layer id="1" name="ground" width="2" height="2" opacity="0.5">
+  <data encoding="csv">
+1,2,
+3,4
+</data>
+ </layer>
+ <layer id="2" name="walls" width="2" height="2" visible="0">
+  <properties>
+   <property name="collision" type="bool" value="true"/>
+  </properties>
+  <data encoding="base64" compression="zlib">` + base64.StdEncoding.EncodeToString(packed.Bytes()) + `</data>
+ </layer>
+ <objectgroup id="3" name="objects">
+  <object id="1" type="spawn" x="4" y="4"><point/></object>
+  <object id="2" class="capture_point" x="0" y="0" width="16" height="16"><ellipse/></object>
+ </objectgroup>
+</map>`
+	tsx := `<?xml version="1.0" encoding="UTF-8"?>
+<tileset name="tiles" tilewidth="8" tileheight="8" tilecount="8" columns="4" spacing="1" margin="1">
+ <image source="../img/tiles.png" width="37" height="19"/>
+</tileset>`
+
+	fsys := fstest.MapFS{
+		"maps/arena.tmx":      {Data: []byte(tmx)},
+		"maps/sets/tiles.tsx": {Data: []byte(tsx)},
+	}
+	m, err := Load(fsys, "maps/arena.tmx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := m.Tilesets[0]
+	if ts.FirstGID != 1 || ts.Image != "maps/img/tiles.png" {
+		t.Fatalf("набор тайлов: firstgid %d, изображение %q", ts.FirstGID, ts.Image)
+	}
+	if x, y, _, _, _ := ts.TileRect(6); x != 10 || y != 10 {
+		t.Fatalf("TileRect с отступами = %d, %d, ожидалось 10, 10", x, y)
+	}
+	ground, walls := m.Layers[0], m.Layers[1]
+	if ground.Opacity != 0.5 || !ground.Visible || walls.Visible {
+		t.Fatal("атрибуты слоев разобраны неверно")
+	}
+	if ground.Tile(1, 1) != 4 || walls.Tile(1, 0) != 5 || walls.Tile(0, 0) != 0 {
+		t.Fatalf("данные слоев: %v, %v", ground.Data, walls.Data)
+	}
+
+	a := m.Arena()
+	if len(a.Spawns) != 1 || len(a.CapturePoints) != 1 || a.CapturePoints[0].Radius != 8 {
+		t.Fatalf("арена: %+v", a)
+	}
+	if !a.Solid(12, 4) || a.Solid(4, 4) {
+		t.Fatal("препятствия из слоя в base64 разобраны неверно")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := map[string]string{
+		"bad.tmj":   `{"width": 2, "height": 1, "tilewidth": 8, "tileheight": 8, "layers": [{"type": "tilelayer", "name": "l", "width": 2, "height": 1, "data": [1]}]}`,
+		"iso.tmj":   `{"width": 1, "height": 1, "tilewidth": 8, "tileheight": 8, "orientation": "isometric"}`,
+		"zstd.tmx":  `<map width="1" height="1" tilewidth="8" tileheight="8"><layer name="l" width="1" height="1"><data encoding="base64" compression="zstd">AAAAAA==</data></layer></map>`,
+		"group.tmx": `<map width="1" height="1" tilewidth="8" tileheight="8"><group name="g"/></map>`,
+	}
+	fsys := fstest.MapFS{}
+	for name, data := range cases {
+		fsys[name] = &fstest.MapFile{Data: []byte(data)}
+	}
+	for name := range cases {
+		if _, err := Load(fsys, name); err == nil {
+			t.Errorf("%s: ожидалась ошибка", name)
+		}
+	}
+}
+
+// Встроенная карта арены должна читаться и содержать все, что нужно уровню
+func TestEmbeddedArena(t *testing.T) {
+	m, err := Load(os.DirFS("../.."), "maps/arena.tmj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := m.Arena()
+	if len(a.Spawns) == 0 || len(a.CapturePoints) == 0 || len(a.Colliders) == 0 {
+		t.Fatalf("на арене нет точек появления, захвата или препятствий: %+v", a)
+	}
+	for _, s := range a.Spawns {
+		if a.Solid(s.X, s.Y) {
+			t.Errorf("точка появления %v внутри препятствия", s)
+		}
+	}
+	for _, cp := range a.CapturePoints {
+		if a.Solid(cp.X, cp.Y) {
+			t.Errorf("точка захвата %v внутри препятствия", cp)
+		}
+	}
+}
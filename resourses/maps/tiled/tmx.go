@@ -0,0 +1,229 @@
+package tiled
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+)
+
+// Структуры формата TMX; в общий Map переводятся функцией parseTMX
+
+type tmxMap struct {
+	Width        int              `xml:"width,attr"`
+	Height       int              `xml:"height,attr"`
+	TileWidth    int              `xml:"tilewidth,attr"`
+	TileHeight   int              `xml:"tileheight,attr"`
+	Orientation  string           `xml:"orientation,attr"`
+	Infinite     bool             `xml:"infinite,attr"`
+	Tilesets     []tmxTileset     `xml:"tileset"`
+	Layers       []tmxLayer       `xml:"layer"`
+	ObjectGroups []tmxObjectGroup `xml:"objectgroup"`
+	Groups       []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"group"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+type tmxTileset struct {
+	FirstGID   uint32 `xml:"firstgid,attr"`
+	Source     string `xml:"source,attr"` // Внешний набор .tsx
+	Name       string `xml:"name,attr"`
+	TileWidth  int    `xml:"tilewidth,attr"`
+	TileHeight int    `xml:"tileheight,attr"`
+	Columns    int    `xml:"columns,attr"`
+	TileCount  int    `xml:"tilecount,attr"`
+	Margin     int    `xml:"margin,attr"`
+	Spacing    int    `xml:"spacing,attr"`
+	Image      struct {
+		Source string `xml:"source,attr"`
+		Width  int    `xml:"width,attr"`
+		Height int    `xml:"height,attr"`
+	} `xml:"image"`
+}
+
+type tmxLayer struct {
+	Name       string        `xml:"name,attr"`
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	Visible    *int          `xml:"visible,attr"` // Без атрибута слой виден
+	Opacity    *float64      `xml:"opacity,attr"` // Без атрибута слой непрозрачен
+	OffsetX    float64       `xml:"offsetx,attr"`
+	OffsetY    float64       `xml:"offsety,attr"`
+	Properties []tmxProperty `xml:"properties>property"`
+	Data       struct {
+		Encoding    string `xml:"encoding,attr"`
+		Compression string `xml:"compression,attr"`
+		Text        string `xml:",chardata"`
+		Tiles       []struct {
+			GID uint32 `xml:"gid,attr"`
+		} `xml:"tile"` // Данные без кодировки: по элементу на тайл
+	} `xml:"data"`
+}
+
+type tmxObjectGroup struct {
+	Name    string `xml:"name,attr"`
+	Objects []struct {
+		ID         int           `xml:"id,attr"`
+		Name       string        `xml:"name,attr"`
+		Type       string        `xml:"type,attr"`
+		Class      string        `xml:"class,attr"` // Так атрибут называется в Tiled 1.9
+		X          float64       `xml:"x,attr"`
+		Y          float64       `xml:"y,attr"`
+		Width      float64       `xml:"width,attr"`
+		Height     float64       `xml:"height,attr"`
+		Ellipse    *struct{}     `xml:"ellipse"`
+		Point      *struct{}     `xml:"point"`
+		Properties []tmxProperty `xml:"properties>property"`
+	} `xml:"object"`
+}
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"` // Многострочные значения пишутся внутри элемента
+}
+
+// parseTMX читает карту в формате TMX; dir - каталог карты для путей изображений
+func parseTMX(data []byte, dir string, readTileset tilesetReader) (*Map, error) {
+	var tm tmxMap
+	if err := xml.Unmarshal(data, &tm); err != nil {
+		return nil, err
+	}
+	if tm.Orientation != "" && tm.Orientation != "orthogonal" {
+		return nil, fmt.Errorf("ориентация %q не поддерживается, нужна orthogonal", tm.Orientation)
+	}
+	if tm.Infinite {
+		return nil, fmt.Errorf("бесконечные карты не поддерживаются")
+	}
+	if len(tm.Groups) > 0 {
+		return nil, fmt.Errorf("слой %s: группы слоев не поддерживаются", tm.Groups[0].Name)
+	}
+
+	m := &Map{
+		Width:      tm.Width,
+		Height:     tm.Height,
+		TileWidth:  tm.TileWidth,
+		TileHeight: tm.TileHeight,
+		Properties: tmxProperties(tm.Properties),
+	}
+	for _, tt := range tm.Tilesets {
+		if tt.Source != "" {
+			ts, err := readTileset(tt.Source)
+			if err != nil {
+				return nil, err
+			}
+			ts.FirstGID = tt.FirstGID
+			m.Tilesets = append(m.Tilesets, ts)
+			continue
+		}
+		ts := tt.tileset()
+		ts.Image = path.Join(dir, ts.Image)
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+	for _, tl := range tm.Layers {
+		layer, err := tl.tileLayer()
+		if err != nil {
+			return nil, fmt.Errorf("слой %s: %w", tl.Name, err)
+		}
+		m.Layers = append(m.Layers, layer)
+	}
+	for _, group := range tm.ObjectGroups {
+		for _, to := range group.Objects {
+			class := to.Type
+			if class == "" {
+				class = to.Class
+			}
+			m.Objects = append(m.Objects, Object{
+				ID:         to.ID,
+				Name:       to.Name,
+				Class:      class,
+				Layer:      group.Name,
+				X:          to.X,
+				Y:          to.Y,
+				Width:      to.Width,
+				Height:     to.Height,
+				Ellipse:    to.Ellipse != nil,
+				Point:      to.Point != nil,
+				Properties: tmxProperties(to.Properties),
+			})
+		}
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// parseTSX читает внешний набор тайлов в формате TSX
+func parseTSX(data []byte) (*Tileset, error) {
+	var tt tmxTileset
+	if err := xml.Unmarshal(data, &tt); err != nil {
+		return nil, err
+	}
+	return tt.tileset(), nil
+}
+
+func (tt tmxTileset) tileset() *Tileset {
+	return &Tileset{
+		FirstGID:    tt.FirstGID,
+		Name:        tt.Name,
+		Image:       tt.Image.Source,
+		ImageWidth:  tt.Image.Width,
+		ImageHeight: tt.Image.Height,
+		TileWidth:   tt.TileWidth,
+		TileHeight:  tt.TileHeight,
+		Columns:     tt.Columns,
+		TileCount:   tt.TileCount,
+		Margin:      tt.Margin,
+		Spacing:     tt.Spacing,
+	}
+}
+
+func (tl tmxLayer) tileLayer() (*Layer, error) {
+	layer := &Layer{
+		Name:       tl.Name,
+		Width:      tl.Width,
+		Height:     tl.Height,
+		Visible:    tl.Visible == nil || *tl.Visible != 0,
+		Opacity:    1,
+		OffsetX:    tl.OffsetX,
+		OffsetY:    tl.OffsetY,
+		Properties: tmxProperties(tl.Properties),
+	}
+	if tl.Opacity != nil {
+		layer.Opacity = *tl.Opacity
+	}
+
+	var err error
+	switch tl.Data.Encoding {
+	case "csv":
+		layer.Data, err = decodeCSV(tl.Data.Text)
+	case "base64":
+		layer.Data, err = decodeBase64(tl.Data.Text, tl.Data.Compression)
+	case "":
+		for _, tile := range tl.Data.Tiles {
+			layer.Data = append(layer.Data, tile.GID)
+		}
+	default:
+		err = fmt.Errorf("кодировка %q не поддерживается", tl.Data.Encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return layer, nil
+}
+
+func tmxProperties(props []tmxProperty) Properties {
+	if len(props) == 0 {
+		return nil
+	}
+	p := make(Properties, len(props))
+	for _, prop := range props {
+		if prop.Value == "" {
+			p[prop.Name] = prop.Text
+		} else {
+			p[prop.Name] = prop.Value
+		}
+	}
+	return p
+}
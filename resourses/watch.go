@@ -0,0 +1,102 @@
+package resourses
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Watcher периодически опрашивает каталог переопределения и сообщает,
+// какие файлы изменились. Используется для горячей перезагрузки в режиме разработки.
+type Watcher struct {
+	Changes chan []string // Пути измененных, добавленных и удаленных файлов внутри ресурсов
+	dir     string
+	stop    chan struct{}
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch начинает опрос каталога dir с периодом interval
+func Watch(dir string, interval time.Duration) (*Watcher, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		Changes: make(chan []string),
+		dir:     dir,
+		stop:    make(chan struct{}),
+	}
+	go w.run(interval)
+	return w, nil
+}
+
+// Close останавливает опрос
+func (w *Watcher) Close() {
+	close(w.stop)
+}
+
+func (w *Watcher) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	prev := w.snapshot()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+
+		current := w.snapshot()
+		var changed []string
+		for name, stamp := range current {
+			if old, ok := prev[name]; !ok || old != stamp {
+				changed = append(changed, name)
+			}
+		}
+		for name := range prev {
+			if _, ok := current[name]; !ok {
+				changed = append(changed, name)
+			}
+		}
+		prev = current
+
+		if len(changed) == 0 {
+			continue
+		}
+		select {
+		case w.Changes <- changed:
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// snapshot собирает время изменения и размер всех файлов каталога
+func (w *Watcher) snapshot() map[string]fileStamp {
+	files := make(map[string]fileStamp)
+	err := filepath.WalkDir(w.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // Файл удалили во время обхода
+		}
+		rel, err := filepath.Rel(w.dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	if err != nil {
+		slog.Warn("Ошибка опроса каталога ресурсов", "err", err)
+	}
+	return files
+}
@@ -0,0 +1,25 @@
+// Package scenes содержит имена сцен, по которым уровни переключают друг друга.
+// Сами сцены регистрируются в gamestate вместе с фабриками и нужными им ресурсами.
+package scenes
+
+const (
+	Menu     = "menu"   // Ввод имени и выбор скина
+	Level1   = "level1" // Сетевая игра с точками захвата
+	Level2   = "level2"
+	Level5   = "level5"
+	Error    = "error"    // Сообщение об ошибке; открывается самой игрой, а не уровнями
+	Pause    = "pause"    // Пауза поверх Level1
+	Controls = "controls" // Подсказка по управлению
+	Settings = "settings" // Настройки окна и графики
+)
+
+// Transition анимация смены сцен
+type Transition int
+
+const (
+	Cut       Transition = iota // Мгновенная смена без анимации
+	Fade                        // Затемнение и появление из черного
+	Crossfade                   // Плавное наложение новой сцены на старую
+	Wipe                        // Новая сцена надвигается слева направо
+	Pixelate                    // Старая сцена распадается на крупные пиксели, новая собирается из них
+)
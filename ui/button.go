@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"main.go/controls"
+)
+
+// Button прямоугольная кнопка, нажимаемая мышью или касанием
+type Button struct {
+	Rect  image.Rectangle // Положение на экране
+	Label string
+	Color color.Color
+}
+
+// NewButton создает кнопку; положение задается через Rect при раскладке экрана
+func NewButton(label string, clr color.Color) *Button {
+	return &Button{Label: label, Color: clr}
+}
+
+// Contains проверяет, попадает ли точка экрана в кнопку
+func (b *Button) Contains(x, y float64) bool {
+	return image.Pt(int(x), int(y)).In(b.Rect)
+}
+
+// Clicked сообщает, что кнопку нажали мышью или касанием в текущем тике
+func (b *Button) Clicked(src controls.Source) bool {
+	return src.ActionIsJustPressed(controls.ActionPointer) && b.Contains(src.PointerPos())
+}
+
+// Draw рисует кнопку с подписью
+func (b *Button) Draw(screen *ebiten.Image) {
+	vector.DrawFilledRect(screen, float32(b.Rect.Min.X), float32(b.Rect.Min.Y), float32(b.Rect.Dx()), float32(b.Rect.Dy()), b.Color, false)
+	ebitenutil.DebugPrintAt(screen, b.Label, b.Rect.Min.X+10, b.Rect.Min.Y+b.Rect.Dy()/2-10)
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"main.go/controls"
+)
+
+var (
+	listButtonColor         = color.RGBA{60, 60, 60, 255}
+	selectedListButtonColor = color.RGBA{0, 120, 200, 255}
+)
+
+// ButtonList вертикальный список кнопок: выбор стрелками или геймпадом
+// с подтверждением через Confirm, либо щелчком или касанием
+type ButtonList struct {
+	Buttons  []*Button
+	Selected int // Подсвеченная кнопка
+}
+
+// NewButtonList создает список кнопок с заданными подписями
+func NewButtonList(labels ...string) *ButtonList {
+	l := &ButtonList{}
+	for _, label := range labels {
+		l.Buttons = append(l.Buttons, NewButton(label, listButtonColor))
+	}
+	return l
+}
+
+// Update обрабатывает ввод и возвращает индекс нажатой кнопки или -1
+func (l *ButtonList) Update(src controls.Source) int {
+	if len(l.Buttons) == 0 {
+		return -1
+	}
+	if src.ActionIsJustPressed(controls.ActionMoveUp) {
+		l.Selected = (l.Selected + len(l.Buttons) - 1) % len(l.Buttons)
+	}
+	if src.ActionIsJustPressed(controls.ActionMoveDown) {
+		l.Selected = (l.Selected + 1) % len(l.Buttons)
+	}
+	for i, b := range l.Buttons {
+		if b.Clicked(src) {
+			l.Selected = i
+			return i
+		}
+	}
+	if src.ActionIsJustPressed(controls.ActionConfirm) {
+		return l.Selected
+	}
+	return -1
+}
+
+// Layout располагает кнопки столбцом с левым верхним углом в (x, y)
+func (l *ButtonList) Layout(x, y, width, height, gap int) {
+	for i, b := range l.Buttons {
+		top := y + i*(height+gap)
+		b.Rect = image.Rect(x, top, x+width, top+height)
+	}
+}
+
+// Draw рисует кнопки, выбранная подсвечена
+func (l *ButtonList) Draw(screen *ebiten.Image) {
+	for i, b := range l.Buttons {
+		b.Color = listButtonColor
+		if i == l.Selected {
+			b.Color = selectedListButtonColor
+		}
+		b.Draw(screen)
+	}
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Clipboard доступ к буферу обмена. Ebiten не предоставляет его сам,
+// поэтому по умолчанию используются системные утилиты (см. SystemClipboard).
+type Clipboard interface {
+	ReadText() (string, error)
+	WriteText(text string) error
+}
+
+// SystemClipboard работает с буфером обмена через утилиты операционной системы
+type SystemClipboard struct{}
+
+func (SystemClipboard) ReadText() (string, error) {
+	out, err := runClipboardCommand(pasteCommands, "")
+	if err != nil {
+		return "", err
+	}
+	// Утилиты часто добавляют перевод строки в конце
+	return strings.TrimRight(out, "\r\n"), nil
+}
+
+func (SystemClipboard) WriteText(text string) error {
+	_, err := runClipboardCommand(copyCommands, text)
+	return err
+}
+
+// runClipboardCommand пробует команды по очереди, пока одна из них не сработает
+func runClipboardCommand(commands [][]string, stdin string) (string, error) {
+	var lastErr error = exec.ErrNotFound
+	for _, args := range commands {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		cmd := exec.Command(path, args[1:]...)
+		if stdin != "" {
+			cmd.Stdin = strings.NewReader(stdin)
+		}
+		out, err := cmd.Output()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return string(out), nil
+	}
+	return "", lastErr
+}
@@ -0,0 +1,6 @@
+package ui
+
+var (
+	pasteCommands = [][]string{{"pbpaste"}}
+	copyCommands  = [][]string{{"pbcopy"}}
+)
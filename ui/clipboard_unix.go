@@ -0,0 +1,16 @@
+//go:build !windows && !darwin
+
+package ui
+
+var (
+	pasteCommands = [][]string{
+		{"wl-paste", "--no-newline"},
+		{"xclip", "-selection", "clipboard", "-o"},
+		{"xsel", "--clipboard", "--output"},
+	}
+	copyCommands = [][]string{
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	}
+)
@@ -0,0 +1,6 @@
+package ui
+
+var (
+	pasteCommands = [][]string{{"powershell", "-NoProfile", "-Command", "Get-Clipboard"}}
+	copyCommands  = [][]string{{"clip"}}
+)
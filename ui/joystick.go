@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	input "github.com/quasilyte/ebitengine-input"
+	"main.go/controls"
+)
+
+const joystickDeadZone = 0.3 // Доля радиуса, в пределах которой движение не засчитывается
+
+// VirtualJoystick экранный джойстик для движения касанием или мышью.
+// Оборачивает источник ввода и добавляет к нему действия движения,
+// поэтому уровень продолжает читать только controls.Source.
+type VirtualJoystick struct {
+	controls.Source
+	Radius float64 // Радиус основания
+
+	centerX, centerY float64
+	active           bool    // Указатель удерживается, начав движение внутри основания
+	knobX, knobY     float64 // Смещение ручки от центра
+	dirs, prevDirs   map[input.Action]bool
+}
+
+// NewVirtualJoystick оборачивает источник ввода экранным джойстиком
+func NewVirtualJoystick(src controls.Source, radius float64) *VirtualJoystick {
+	return &VirtualJoystick{
+		Source:   src,
+		Radius:   radius,
+		dirs:     make(map[input.Action]bool),
+		prevDirs: make(map[input.Action]bool),
+	}
+}
+
+// SetArea размещает джойстик в левом нижнем углу области экрана
+func (j *VirtualJoystick) SetArea(area image.Rectangle) {
+	j.centerX = float64(area.Min.X) + j.Radius*1.5
+	j.centerY = float64(area.Max.Y) - j.Radius*1.5
+}
+
+// Reset отпускает джойстик: пока указатель не нажмут заново, движения нет
+func (j *VirtualJoystick) Reset() {
+	j.active = false
+	j.knobX, j.knobY = 0, 0
+	clear(j.dirs)
+	clear(j.prevDirs)
+}
+
+// Update пересчитывает направление по указателю. Сам обернутый источник
+// обновляется игрой, поэтому здесь его Update не вызывается.
+func (j *VirtualJoystick) Update() {
+	j.dirs, j.prevDirs = j.prevDirs, j.dirs
+	for a := range j.dirs {
+		delete(j.dirs, a)
+	}
+
+	x, y := j.Source.PointerPos()
+	dx, dy := x-j.centerX, y-j.centerY
+	switch {
+	case !j.Source.ActionIsPressed(controls.ActionPointer):
+		j.active = false
+	case j.Source.ActionIsJustPressed(controls.ActionPointer) && math.Hypot(dx, dy) <= j.Radius:
+		j.active = true
+	}
+
+	if !j.active {
+		j.knobX, j.knobY = 0, 0
+		return
+	}
+
+	// Ручка не выходит за пределы основания
+	if dist := math.Hypot(dx, dy); dist > j.Radius {
+		dx, dy = dx/dist*j.Radius, dy/dist*j.Radius
+	}
+	j.knobX, j.knobY = dx, dy
+
+	deadZone := j.Radius * joystickDeadZone
+	j.dirs[controls.ActionMoveRight] = dx > deadZone
+	j.dirs[controls.ActionMoveLeft] = dx < -deadZone
+	j.dirs[controls.ActionMoveDown] = dy > deadZone
+	j.dirs[controls.ActionMoveUp] = dy < -deadZone
+}
+
+func (j *VirtualJoystick) ActionIsPressed(action input.Action) bool {
+	return j.Source.ActionIsPressed(action) || j.dirs[action]
+}
+
+func (j *VirtualJoystick) ActionIsJustPressed(action input.Action) bool {
+	return j.Source.ActionIsJustPressed(action) || j.dirs[action] && !j.prevDirs[action]
+}
+
+// Draw рисует основание и ручку джойстика
+func (j *VirtualJoystick) Draw(screen *ebiten.Image) {
+	vector.StrokeCircle(screen, float32(j.centerX), float32(j.centerY), float32(j.Radius), 3, color.RGBA{255, 255, 255, 120}, true)
+	vector.DrawFilledCircle(screen, float32(j.centerX+j.knobX), float32(j.centerY+j.knobY), float32(j.Radius/2.5), color.RGBA{255, 255, 255, 160}, true)
+}
@@ -0,0 +1,323 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	input "github.com/quasilyte/ebitengine-input"
+	"main.go/controls"
+)
+
+const (
+	repeatDelay    = 24 // Кадров до начала автоповтора клавиши
+	repeatInterval = 3  // Кадров между повторами при удержании клавиши
+)
+
+// TextInput однострочное поле ввода текста с курсором и выделением.
+// Вся работа с текстом ведется в рунах, поэтому кириллица и другие
+// многобайтовые символы редактируются корректно.
+type TextInput struct {
+	MaxLength int                     // Максимальная длина в рунах (0 - без ограничения)
+	Filter    func(r rune) bool       // Возвращает false для символов, которые нельзя вводить
+	Validate  func(text string) error // Проверка значения целиком после каждого изменения
+	Clipboard Clipboard               // Источник для вставки и копирования
+	Focused   bool                    // Обрабатывать ли ввод
+
+	text   []rune
+	cursor int                  // Позиция курсора (между рунами)
+	anchor int                  // Второй конец выделения, равен cursor, если выделения нет
+	err    error                // Результат последней проверки Validate
+	held   map[input.Action]int // Сколько тиков удерживается клавиша редактирования
+}
+
+// NewTextInput создает поле ввода с ограничением длины в рунах
+func NewTextInput(maxLength int) *TextInput {
+	return &TextInput{
+		MaxLength: maxLength,
+		Clipboard: SystemClipboard{},
+		Focused:   true,
+		held:      make(map[input.Action]int),
+	}
+}
+
+// Text возвращает текущее значение поля
+func (t *TextInput) Text() string {
+	return string(t.text)
+}
+
+// SetText заменяет значение поля и ставит курсор в конец
+func (t *TextInput) SetText(s string) {
+	t.text = t.text[:0]
+	t.cursor, t.anchor = 0, 0
+	t.insert(s)
+}
+
+// Len возвращает длину текста в рунах
+func (t *TextInput) Len() int {
+	return len(t.text)
+}
+
+// Cursor возвращает позицию курсора в рунах
+func (t *TextInput) Cursor() int {
+	return t.cursor
+}
+
+// Selection возвращает границы выделения в рунах (start <= end)
+func (t *TextInput) Selection() (start, end int) {
+	if t.anchor < t.cursor {
+		return t.anchor, t.cursor
+	}
+	return t.cursor, t.anchor
+}
+
+// HasSelection сообщает, выделен ли какой-либо текст
+func (t *TextInput) HasSelection() bool {
+	return t.anchor != t.cursor
+}
+
+// SelectedText возвращает выделенный фрагмент
+func (t *TextInput) SelectedText() string {
+	start, end := t.Selection()
+	return string(t.text[start:end])
+}
+
+// Err возвращает ошибку последней проверки значения
+func (t *TextInput) Err() error {
+	return t.err
+}
+
+// Valid сообщает, прошло ли текущее значение проверку
+func (t *TextInput) Valid() bool {
+	return t.err == nil
+}
+
+// Insert вставляет строку на место курсора, заменяя выделение.
+// Символы, не прошедшие Filter, и всё, что не помещается в MaxLength, отбрасывается.
+func (t *TextInput) Insert(s string) {
+	t.insert(s)
+}
+
+// MoveCursor сдвигает курсор на delta рун; при selecting выделение расширяется
+func (t *TextInput) MoveCursor(delta int, selecting bool) {
+	if !selecting && t.HasSelection() && delta != 0 {
+		// Без Shift стрелка схлопывает выделение к соответствующему краю
+		start, end := t.Selection()
+		if delta < 0 {
+			t.setCursor(start, false)
+		} else {
+			t.setCursor(end, false)
+		}
+		return
+	}
+	t.setCursor(t.cursor+delta, selecting)
+}
+
+// Home переносит курсор в начало строки
+func (t *TextInput) Home(selecting bool) {
+	t.setCursor(0, selecting)
+}
+
+// End переносит курсор в конец строки
+func (t *TextInput) End(selecting bool) {
+	t.setCursor(len(t.text), selecting)
+}
+
+// SelectAll выделяет весь текст
+func (t *TextInput) SelectAll() {
+	t.anchor = 0
+	t.cursor = len(t.text)
+}
+
+// Backspace удаляет выделение или руну слева от курсора
+func (t *TextInput) Backspace() {
+	if t.deleteSelection() {
+		t.validate()
+		return
+	}
+	if t.cursor == 0 {
+		return
+	}
+	t.text = append(t.text[:t.cursor-1], t.text[t.cursor:]...)
+	t.setCursor(t.cursor-1, false)
+	t.validate()
+}
+
+// Delete удаляет выделение или руну справа от курсора
+func (t *TextInput) Delete() {
+	if t.deleteSelection() {
+		t.validate()
+		return
+	}
+	if t.cursor >= len(t.text) {
+		return
+	}
+	t.text = append(t.text[:t.cursor], t.text[t.cursor+1:]...)
+	t.validate()
+}
+
+// Paste вставляет содержимое буфера обмена на место курсора
+func (t *TextInput) Paste() error {
+	if t.Clipboard == nil {
+		return nil
+	}
+	s, err := t.Clipboard.ReadText()
+	if err != nil {
+		return err
+	}
+	t.insert(s)
+	return nil
+}
+
+// Copy помещает выделенный текст в буфер обмена
+func (t *TextInput) Copy() error {
+	if t.Clipboard == nil || !t.HasSelection() {
+		return nil
+	}
+	return t.Clipboard.WriteText(t.SelectedText())
+}
+
+// Cut копирует выделенный текст в буфер обмена и удаляет его
+func (t *TextInput) Cut() error {
+	if err := t.Copy(); err != nil {
+		return err
+	}
+	if t.deleteSelection() {
+		t.validate()
+	}
+	return nil
+}
+
+// Update обрабатывает ввод через действия controls; вызывается один раз за тик.
+// Вставка из буфера обмена приходит от источника как обычный набранный текст.
+func (t *TextInput) Update(src controls.Source) error {
+	if !t.Focused {
+		return nil
+	}
+
+	selecting := src.ActionIsPressed(controls.ActionTextSelect)
+
+	switch {
+	case src.ActionIsJustPressed(controls.ActionTextSelectAll):
+		t.SelectAll()
+		return nil
+	case src.ActionIsJustPressed(controls.ActionTextCopy):
+		return t.Copy()
+	case src.ActionIsJustPressed(controls.ActionTextCut):
+		return t.Cut()
+	}
+
+	// Обычные символы
+	if text := src.InputText(); text != "" {
+		t.insert(text)
+	}
+
+	// Редактирование и перемещение курсора
+	if t.repeating(src, controls.ActionTextBackspace) {
+		t.Backspace()
+	}
+	if t.repeating(src, controls.ActionTextDelete) {
+		t.Delete()
+	}
+	if t.repeating(src, controls.ActionTextLeft) {
+		t.MoveCursor(-1, selecting)
+	}
+	if t.repeating(src, controls.ActionTextRight) {
+		t.MoveCursor(1, selecting)
+	}
+	if src.ActionIsJustPressed(controls.ActionTextHome) {
+		t.Home(selecting)
+	}
+	if src.ActionIsJustPressed(controls.ActionTextEnd) {
+		t.End(selecting)
+	}
+
+	return nil
+}
+
+// Display возвращает текст для отрисовки: "|" на месте курсора,
+// выделение заключено в квадратные скобки
+func (t *TextInput) Display() string {
+	var b strings.Builder
+	start, end := t.Selection()
+	for i := 0; i <= len(t.text); i++ {
+		if t.HasSelection() && i == start {
+			b.WriteRune('[')
+		}
+		if t.Focused && i == t.cursor && !t.HasSelection() {
+			b.WriteRune('|')
+		}
+		if t.HasSelection() && i == end {
+			b.WriteRune(']')
+		}
+		if i < len(t.text) {
+			b.WriteRune(t.text[i])
+		}
+	}
+	return b.String()
+}
+
+func (t *TextInput) insert(s string) {
+	t.deleteSelection()
+	for _, r := range s {
+		if unicode.IsControl(r) {
+			continue // Переводы строк и прочие управляющие символы в однострочном поле не нужны
+		}
+		if t.Filter != nil && !t.Filter(r) {
+			continue
+		}
+		if t.MaxLength > 0 && len(t.text) >= t.MaxLength {
+			break
+		}
+		t.text = append(t.text, 0)
+		copy(t.text[t.cursor+1:], t.text[t.cursor:])
+		t.text[t.cursor] = r
+		t.cursor++
+	}
+	t.anchor = t.cursor
+	t.validate()
+}
+
+func (t *TextInput) deleteSelection() bool {
+	if !t.HasSelection() {
+		return false
+	}
+	start, end := t.Selection()
+	t.text = append(t.text[:start], t.text[end:]...)
+	t.cursor, t.anchor = start, start
+	return true
+}
+
+func (t *TextInput) setCursor(pos int, selecting bool) {
+	if pos < 0 {
+		pos = 0
+	}
+	if pos > len(t.text) {
+		pos = len(t.text)
+	}
+	t.cursor = pos
+	if !selecting {
+		t.anchor = pos
+	}
+}
+
+func (t *TextInput) validate() {
+	if t.Validate != nil {
+		t.err = t.Validate(string(t.text))
+	} else {
+		t.err = nil
+	}
+}
+
+// repeating срабатывает при нажатии и затем периодически при удержании
+func (t *TextInput) repeating(src controls.Source, action input.Action) bool {
+	if !src.ActionIsPressed(action) {
+		t.held[action] = 0
+		return false
+	}
+	t.held[action]++
+	d := t.held[action]
+	if d == 1 {
+		return true
+	}
+	return d >= repeatDelay && (d-repeatDelay)%repeatInterval == 0
+}
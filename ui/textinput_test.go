@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestTextInput(t *testing.T) {
+	cases := []struct {
+		name    string
+		max     int
+		edit    func(in *TextInput)
+		text    string
+		cursor  int
+		display string
+	}{
+		{
+			name:    "вставка",
+			edit:    func(in *TextInput) { in.Insert("abc") },
+			text:    "abc",
+			cursor:  3,
+			display: "abc|",
+		},
+		{
+			name:    "вставка в середину",
+			edit:    func(in *TextInput) { in.Insert("ac"); in.MoveCursor(-1, false); in.Insert("b") },
+			text:    "abc",
+			cursor:  2,
+			display: "ab|c",
+		},
+		{
+			name:    "управляющие символы отбрасываются",
+			edit:    func(in *TextInput) { in.Insert("a\nb\tc") },
+			text:    "abc",
+			cursor:  3,
+			display: "abc|",
+		},
+		{
+			name:    "MaxLength в рунах",
+			max:     4,
+			edit:    func(in *TextInput) { in.Insert("привет") },
+			text:    "прив",
+			cursor:  4,
+			display: "прив|",
+		},
+		{
+			name:    "MaxLength при вставке в середину",
+			max:     3,
+			edit:    func(in *TextInput) { in.Insert("ab"); in.Home(false); in.Insert("xyz") },
+			text:    "xab",
+			cursor:  1,
+			display: "x|ab",
+		},
+		{
+			name:    "Backspace по кириллице",
+			edit:    func(in *TextInput) { in.Insert("ёжик"); in.Backspace(); in.Backspace() },
+			text:    "ёж",
+			cursor:  2,
+			display: "ёж|",
+		},
+		{
+			name:    "Backspace в начале строки",
+			edit:    func(in *TextInput) { in.Insert("ёж"); in.Home(false); in.Backspace() },
+			text:    "ёж",
+			cursor:  0,
+			display: "|ёж",
+		},
+		{
+			name:    "Backspace в середине",
+			edit:    func(in *TextInput) { in.Insert("мяч"); in.MoveCursor(-1, false); in.Backspace() },
+			text:    "мч",
+			cursor:  1,
+			display: "м|ч",
+		},
+		{
+			name:    "Delete справа от курсора",
+			edit:    func(in *TextInput) { in.Insert("мяч"); in.Home(false); in.Delete() },
+			text:    "яч",
+			cursor:  0,
+			display: "|яч",
+		},
+		{
+			name:    "курсор не выходит за границы",
+			edit:    func(in *TextInput) { in.Insert("да"); in.MoveCursor(5, false); in.MoveCursor(-10, false) },
+			text:    "да",
+			cursor:  0,
+			display: "|да",
+		},
+		{
+			name:    "Home и End",
+			edit:    func(in *TextInput) { in.Insert("abc"); in.Home(false); in.MoveCursor(1, false); in.End(false) },
+			text:    "abc",
+			cursor:  3,
+			display: "abc|",
+		},
+		{
+			name:    "выделение стрелками",
+			edit:    func(in *TextInput) { in.Insert("кот"); in.MoveCursor(-2, true) },
+			text:    "кот",
+			cursor:  1,
+			display: "к[от]",
+		},
+		{
+			name:    "стрелка без Shift схлопывает выделение",
+			edit:    func(in *TextInput) { in.Insert("кот"); in.SelectAll(); in.MoveCursor(-1, false) },
+			text:    "кот",
+			cursor:  0,
+			display: "|кот",
+		},
+		{
+			name:    "вставка заменяет выделение",
+			edit:    func(in *TextInput) { in.Insert("кот"); in.Home(true); in.Insert("пёс") },
+			text:    "пёс",
+			cursor:  3,
+			display: "пёс|",
+		},
+		{
+			name:    "Backspace удаляет выделение",
+			edit:    func(in *TextInput) { in.Insert("кошка"); in.MoveCursor(-3, true); in.Backspace() },
+			text:    "ко",
+			cursor:  2,
+			display: "ко|",
+		},
+		{
+			name: "Filter",
+			edit: func(in *TextInput) {
+				in.Filter = func(r rune) bool { return !unicode.IsDigit(r) }
+				in.Insert("a1б2")
+			},
+			text:    "aб",
+			cursor:  2,
+			display: "aб|",
+		},
+	}
+	for _, c := range cases {
+		in := NewTextInput(c.max)
+		in.Clipboard = nil // Без системного буфера обмена
+		c.edit(in)
+		if in.Text() != c.text || in.Cursor() != c.cursor || in.Display() != c.display {
+			t.Errorf("%s: %q, курсор %d, %q; ожидалось %q, курсор %d, %q",
+				c.name, in.Text(), in.Cursor(), in.Display(), c.text, c.cursor, c.display)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Package view переводит координаты между окном и виртуальным экраном игры.
+// Игра рисуется для виртуального разрешения, которое вписывается в окно
+// с сохранением пропорций; свободные полосы остаются черными.
+package view
+
+import (
+	"image"
+	"math"
+)
+
+// Виртуальное разрешение, под которое рассчитаны уровни и интерфейс
+const (
+	VirtualWidth  = 1600
+	VirtualHeight = 900
+)
+
+// Viewport область окна, занятая игрой
+type Viewport struct {
+	Rect  image.Rectangle // Область в пикселях экрана, остальное - полосы
+	Scale float64         // Пикселей экрана на единицу виртуального разрешения
+}
+
+// Fit вписывает виртуальный экран virtualW x virtualH в экран screenW x screenH по центру
+func Fit(screenW, screenH, virtualW, virtualH int) Viewport {
+	if screenW <= 0 || screenH <= 0 || virtualW <= 0 || virtualH <= 0 {
+		return Viewport{Scale: 1}
+	}
+	scale := math.Min(float64(screenW)/float64(virtualW), float64(screenH)/float64(virtualH))
+	w := int(math.Round(float64(virtualW) * scale))
+	h := int(math.Round(float64(virtualH) * scale))
+	x := (screenW - w) / 2
+	y := (screenH - h) / 2
+	return Viewport{Rect: image.Rect(x, y, x+w, y+h), Scale: scale}
+}
+
+// ToContent переводит точку экрана в пиксели внутри области игры
+func (v Viewport) ToContent(x, y float64) (float64, float64) {
+	return x - float64(v.Rect.Min.X), y - float64(v.Rect.Min.Y)
+}
+
+// ToVirtual переводит точку экрана в координаты виртуального разрешения
+func (v Viewport) ToVirtual(x, y float64) (float64, float64) {
+	x, y = v.ToContent(x, y)
+	return x / v.Scale, y / v.Scale
+}
+
+// ToScreen переводит координаты виртуального разрешения в точку экрана
+func (v Viewport) ToScreen(x, y float64) (float64, float64) {
+	return x*v.Scale + float64(v.Rect.Min.X), y*v.Scale + float64(v.Rect.Min.Y)
+}
+
+// Contains сообщает, попадает ли точка экрана в область игры, а не на полосы
+func (v Viewport) Contains(x, y float64) bool {
+	return image.Pt(int(math.Floor(x)), int(math.Floor(y))).In(v.Rect)
+}
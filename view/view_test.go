@@ -0,0 +1,42 @@
+package view
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestFitLetterbox(t *testing.T) {
+	// Окно шире 16:9 - полосы слева и справа
+	v := Fit(2000, 900, 1600, 900)
+	if v.Scale != 1 || v.Rect != image.Rect(200, 0, 1800, 900) {
+		t.Fatalf("pillarbox: %+v", v)
+	}
+
+	// Окно выше 16:9 - полосы сверху и снизу
+	v = Fit(800, 900, 1600, 900)
+	if v.Scale != 0.5 || v.Rect != image.Rect(0, 225, 800, 675) {
+		t.Fatalf("letterbox: %+v", v)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	v := Fit(3200, 2000, 1600, 900) // Например, HiDPI с множителем 2
+	sx, sy := v.ToScreen(400, 300)
+	x, y := v.ToVirtual(sx, sy)
+	if math.Abs(x-400) > 1e-9 || math.Abs(y-300) > 1e-9 {
+		t.Fatalf("получено (%v, %v)", x, y)
+	}
+	if !v.Contains(sx, sy) {
+		t.Fatal("точка внутри области игры определена как полоса")
+	}
+	if v.Contains(0, 0) {
+		t.Fatal("точка на полосе определена как область игры")
+	}
+}
+
+func TestFitDegenerate(t *testing.T) {
+	if v := Fit(0, 0, 1600, 900); v.Scale != 1 {
+		t.Fatalf("свернутое окно: %+v", v)
+	}
+}